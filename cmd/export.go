@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"location-svc/internal/config"
+	"location-svc/internal/db"
+	"location-svc/internal/export"
+	"location-svc/internal/models"
+	"location-svc/internal/repositories"
+	"os"
+	"os/signal"
+)
+
+const exportUsage = `usage: location-svc export -level <level> [flags]
+
+Streams every region of a level, ordered by code, to stdout or a file.
+
+flags:`
+
+// runExport menjalankan subcommand export dan mengembalikan exit code
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), exportUsage)
+		fs.PrintDefaults()
+	}
+
+	level := fs.String("level", "", "level to export: propinsi, kabupaten, kecamatan or kelurahan (required)")
+	parent := fs.String("parent", "", "only export children of this parent code")
+	format := fs.String("format", export.FormatGeoJSON, "output format: geojson, geojsonseq or csv")
+	simplify := fs.Float64("simplify", 0, "simplification tolerance in degrees (0-1), rounded down to a zoom tolerance like the API; 0 keeps full resolution")
+	output := fs.String("o", "", "output file (default stdout)")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *level == "" || fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	if !export.IsFormat(*format) {
+		fmt.Fprintln(os.Stderr, "format must be geojson, geojsonseq or csv")
+		return 2
+	}
+	if *simplify < 0 || *simplify > 1 {
+		fmt.Fprintln(os.Stderr, "simplify must be a number between 0 and 1")
+		return 2
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+
+	cfg, err := config.Load(nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	database, err := db.Init(cfg.DB)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to initialize database:", err)
+		return 1
+	}
+	defer database.Close()
+
+	w, err := export.NewWriter(out, *format, *level)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	// Ctrl-C membatalkan query export di server, bukan hanya menghentikan proses
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	repo := repositories.NewLocationRepository(database)
+	count := 0
+	err = repo.ExportLevel(ctx, *level, *parent, repositories.QuantizeTolerance(*simplify), export.Encoding(*format), func(row models.ExportRow) error {
+		count++
+		return w.Write(row)
+	})
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "export failed:", err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "exported %d %s regions\n", count, *level)
+	return 0
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"location-svc/internal/config"
+	"location-svc/internal/db"
+	"location-svc/internal/importer"
+	"os"
+)
+
+const importUsage = `usage: location-svc import -config mapping.json [flags] <file>
+
+Loads one administrative level from a GeoJSON, GeoJSONSeq or shapefile (.shp with .dbf/.prj)
+source. Import levels top-down: propinsi, kabupaten, kecamatan, kelurahan.
+
+flags:`
+
+// runImport menjalankan subcommand import dan mengembalikan exit code
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), importUsage)
+		fs.PrintDefaults()
+	}
+
+	configPath := fs.String("config", "", "attribute mapping config file (JSON, required)")
+	level := fs.String("level", "", "target level, overrides config level")
+	format := fs.String("format", "", "source format: geojson, geojsonseq or shapefile (default: from file extension)")
+	srid := fs.Int("srid", 0, "source EPSG code, overrides config and the source's own CRS")
+	upsert := fs.Bool("upsert", false, "keep existing rows that are not in the source instead of replacing the level")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *configPath == "" || fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	path := fs.Arg(0)
+
+	cfg, err := importer.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if *level != "" {
+		cfg.Level = *level
+	}
+	if *srid != 0 {
+		cfg.SourceSRID = *srid
+	}
+	cfg.Upsert = cfg.Upsert || *upsert
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, "config:", err)
+		return 2
+	}
+
+	if *format == "" {
+		if *format, err = importer.DetectFormat(path); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	}
+
+	src, err := importer.Open(path, *format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer src.Close()
+
+	svcCfg, err := config.Load(nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	database, err := db.Init(svcCfg.DB)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to initialize database:", err)
+		return 1
+	}
+	defer database.Close()
+
+	result, err := importer.Import(database, src, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "import failed, no changes were made:", err)
+		return 1
+	}
+
+	fmt.Printf("imported %d %s features from EPSG:%d: %d inserted, %d updated, %d deleted\n",
+		result.Rows, result.Level, result.SRID, result.Inserted, result.Updated, result.Deleted)
+	return 0
+}
@@ -1,17 +1,9 @@
 package main
 
 import (
-	"location-svc/internal/db"
-	"location-svc/internal/routes"
-	"log"
 	"os"
 
 	_ "location-svc/docs" // Import untuk swagger docs
-
-	"github.com/joho/godotenv"
-	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
-	echoSwagger "github.com/swaggo/echo-swagger"
 )
 
 // @title Location Service API
@@ -20,40 +12,17 @@ import (
 // @host location-svc.nusarithm.id
 // @BasePath /
 func main() {
-	// Inisialisasi Echo
-	if err := godotenv.Load(); err != nil {
-		log.Println("Warning: .env file not found or could not be loaded:", err)
-	}
-
-	e := echo.New()
-
-	// Middleware
-	e.Use(middleware.Logger())
-	e.Use(middleware.Recover())
-	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"*"},
-		AllowMethods: []string{echo.GET, echo.POST, echo.PUT, echo.DELETE},
-		AllowHeaders: []string{"*"},
-	}))
-
-	// Inisialisasi koneksi database
-	database, err := db.Init()
-	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+	// Subcommand CLI; tanpa subcommand binary menjalankan server HTTP
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			os.Exit(runMigrate(os.Args[2:]))
+		case "import":
+			os.Exit(runImport(os.Args[2:]))
+		case "export":
+			os.Exit(runExport(os.Args[2:]))
+		}
 	}
-	defer database.Close()
-
-	// Setup routes
-	routes.Setup(e, database)
 
-	// Swagger endpoint
-	e.GET("/swagger/*", echoSwagger.WrapHandler)
-
-	// Start server
-	PORT := os.Getenv("PORT")
-	if PORT == "" {
-		PORT = "8080"
-	}
-	log.Println("Server starting on :" + PORT)
-	e.Logger.Fatal(e.Start(":" + PORT))
+	os.Exit(runServer(os.Args[1:]))
 }
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"location-svc/internal/cache"
+	"location-svc/internal/compress"
+	"location-svc/internal/config"
+	"location-svc/internal/db"
+	"location-svc/internal/handlers"
+	"location-svc/internal/health"
+	"location-svc/internal/logging"
+	"location-svc/internal/metrics"
+	"location-svc/internal/repositories"
+	"location-svc/internal/routes"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	echoSwagger "github.com/swaggo/echo-swagger"
+)
+
+// runServer menjalankan server HTTP sampai menerima SIGINT/SIGTERM lalu berhenti dengan graceful
+// shutdown, dan mengembalikan exit code
+func runServer(args []string) int {
+	// Konfigurasi dari flag, environment dan .env; nilai yang tidak valid menghentikan startup
+	cfg, err := config.Load(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		slog.Error("Invalid configuration", "error", err)
+		return 2
+	}
+
+	// Logger JSON terstruktur, teks saat ENV=development
+	logger := logging.New(os.Stdout, cfg.Log.Level, cfg.Development())
+	slog.SetDefault(logger)
+
+	// Inisialisasi Echo
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.HTTPErrorHandler = handlers.HTTPErrorHandler
+	m := metrics.New()
+
+	// Middleware
+	e.Use(middleware.RequestID())
+	e.Use(logging.Middleware(logger))
+	e.Use(m.Middleware())
+	e.Use(middleware.Recover())
+	e.Use(compress.Middleware(cfg.Server.CompressionMinSize))
+	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOrigins: cfg.CORS.AllowOrigins,
+		AllowMethods: []string{echo.GET, echo.POST, echo.PUT, echo.DELETE},
+		AllowHeaders: []string{"*"},
+	}))
+
+	// Inisialisasi koneksi database dan verifikasi schema
+	database, err := db.Init(cfg.DB)
+	if err != nil {
+		logger.Error("Failed to initialize database", "error", err)
+		return 1
+	}
+	defer func() {
+		if err := database.Close(); err != nil {
+			logger.Error("Closing database failed", "error", err)
+			return
+		}
+		logger.Info("Database connection pool closed")
+	}()
+
+	// Catat saat koneksi database putus dan pulih selama server berjalan
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go db.Watch(watchCtx, database, cfg.DB.HealthCheckInterval)
+
+	// Query baca dibagi ke read replica yang sehat, kembali ke primary jika tidak ada
+	replicas, err := db.OpenReplicas(database, cfg.DB)
+	if err != nil {
+		logger.Error("Failed to open read replicas", "error", err)
+		return 1
+	}
+	defer func() {
+		stopWatch()
+		if err := replicas.Close(); err != nil {
+			logger.Error("Closing read replicas failed", "error", err)
+		}
+	}()
+	go replicas.Watch(watchCtx, cfg.DB.HealthCheckInterval)
+
+	locationRepo := repositories.NewLocationRepository(database)
+	locationRepo.SetReadRouter(replicas)
+	locationRepo.SetTimeouts(repositories.Timeouts{
+		Search:  cfg.Limits.SearchTimeout,
+		GeoJSON: cfg.Limits.GeoJSONTimeout,
+		Tiles:   cfg.Limits.TilesTimeout,
+		Reverse: cfg.Limits.ReverseTimeout,
+		Export:  cfg.Limits.ExportTimeout,
+	})
+	locationRepo.SetSlowQueryLog(cfg.Log.SlowQueryThreshold)
+	repo := repositories.NewInstrumentedStore(locationRepo, m.ObserveQuery)
+	store := repositories.NewCachedStore(repo, cache.Options{
+		MaxEntries: cfg.Cache.GeoJSONSize,
+		MaxBytes:   int64(cfg.Cache.GeoJSONMaxMB) << 20,
+		TTL:        cfg.Cache.GeoJSONTTL,
+	})
+
+	m.RegisterDB(database, "location")
+	for i, replica := range replicas.Replicas() {
+		m.RegisterDB(replica, fmt.Sprintf("location_replica_%d", i+1))
+	}
+	m.RegisterCache("geojson", store.CacheStats)
+
+	// Pemeriksaan readiness untuk /readyz
+	checks, err := db.ReadinessChecks(database, cfg.Health.MinRows)
+	if err != nil {
+		logger.Error("Failed to initialize readiness checks", "error", err)
+		return 1
+	}
+	if len(cfg.DB.ReplicaURLs) > 0 {
+		checks = append(checks, replicas.Check())
+	}
+	state := health.NewState(cfg.Health.ReadyTimeout, checks...)
+
+	// Setup routes
+	routes.Setup(e, store, cfg, state)
+
+	// Swagger dan Prometheus endpoint
+	e.GET("/swagger/*", echoSwagger.WrapHandler)
+	e.GET("/metrics", m.Handler())
+
+	// Start server
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("Server starting", "addr", cfg.Server.Addr())
+		serverErr <- e.Start(cfg.Server.Addr())
+	}()
+
+	select {
+	case err := <-serverErr:
+		logger.Error("Server stopped", "error", err)
+		return 1
+	case <-ctx.Done():
+		stop()
+	}
+
+	return shutdown(e, state, cfg.Server, logger)
+}
+
+// shutdown menggagalkan readiness, menunggu ShutdownDelay agar load balancer berhenti mengirim
+// request baru, lalu menunggu request yang sedang berjalan selesai paling lama ShutdownTimeout.
+// Request yang belum selesai setelah batas waktu diputus; query database-nya ikut dibatalkan.
+func shutdown(e *echo.Echo, state *health.State, cfg config.ServerConfig, logger *slog.Logger) int {
+	logger.Info("Shutting down", "delay", cfg.ShutdownDelay.String(), "timeout", cfg.ShutdownTimeout.String())
+	state.StartShutdown()
+	time.Sleep(cfg.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := e.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("In-flight requests did not finish in time, closing connections", "error", err)
+		if err := e.Close(); err != nil {
+			logger.Error("Closing server failed", "error", err)
+		}
+		return 1
+	}
+
+	logger.Info("Server stopped")
+	return 0
+}
@@ -0,0 +1,9 @@
+// Package database berisi migrasi schema yang di-embed ke dalam binary
+package database
+
+import "embed"
+
+// Migrations berisi file migrasi NNNN_nama.up.sql dan NNNN_nama.down.sql
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
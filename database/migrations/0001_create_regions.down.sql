@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS kelurahan;
+DROP TABLE IF EXISTS kecamatan;
+DROP TABLE IF EXISTS kabupaten;
+DROP TABLE IF EXISTS propinsi;
@@ -0,0 +1,42 @@
+-- Tables for Indonesian administrative regions (propinsi > kabupaten > kecamatan > kelurahan)
+-- IF NOT EXISTS lets databases created before migrations were introduced be adopted as-is
+
+CREATE EXTENSION IF NOT EXISTS postgis;
+
+CREATE TABLE IF NOT EXISTS propinsi (
+    kd_propinsi VARCHAR(20) PRIMARY KEY,
+    nm_propinsi VARCHAR(255) NOT NULL,
+    geom GEOMETRY(MULTIPOLYGON, 4326)
+);
+
+CREATE TABLE IF NOT EXISTS kabupaten (
+    kd_kabupaten VARCHAR(20) PRIMARY KEY,
+    nm_kabupaten VARCHAR(255) NOT NULL,
+    kd_propinsi VARCHAR(20) NOT NULL REFERENCES propinsi (kd_propinsi),
+    geom GEOMETRY(MULTIPOLYGON, 4326)
+);
+
+CREATE TABLE IF NOT EXISTS kecamatan (
+    kd_kecamatan VARCHAR(20) PRIMARY KEY,
+    nm_kecamatan VARCHAR(255) NOT NULL,
+    kd_kabupaten VARCHAR(20) NOT NULL REFERENCES kabupaten (kd_kabupaten),
+    geom GEOMETRY(MULTIPOLYGON, 4326)
+);
+
+CREATE TABLE IF NOT EXISTS kelurahan (
+    kd_kelurahan VARCHAR(20) PRIMARY KEY,
+    nm_kelurahan VARCHAR(255) NOT NULL,
+    kd_kecamatan VARCHAR(20) NOT NULL REFERENCES kecamatan (kd_kecamatan),
+    geom GEOMETRY(MULTIPOLYGON, 4326)
+);
+
+-- Foreign key indexes for hierarchy filters and joins
+CREATE INDEX IF NOT EXISTS idx_kabupaten_kd_propinsi ON kabupaten (kd_propinsi);
+CREATE INDEX IF NOT EXISTS idx_kecamatan_kd_kabupaten ON kecamatan (kd_kabupaten);
+CREATE INDEX IF NOT EXISTS idx_kelurahan_kd_kecamatan ON kelurahan (kd_kecamatan);
+
+-- Spatial indexes for reverse geocoding and vector tiles
+CREATE INDEX IF NOT EXISTS idx_propinsi_geom ON propinsi USING GIST (geom);
+CREATE INDEX IF NOT EXISTS idx_kabupaten_geom ON kabupaten USING GIST (geom);
+CREATE INDEX IF NOT EXISTS idx_kecamatan_geom ON kecamatan USING GIST (geom);
+CREATE INDEX IF NOT EXISTS idx_kelurahan_geom ON kelurahan USING GIST (geom);
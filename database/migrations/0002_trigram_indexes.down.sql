@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_kelurahan_nm_trgm;
+DROP INDEX IF EXISTS idx_kecamatan_nm_trgm;
+DROP INDEX IF EXISTS idx_kabupaten_nm_trgm;
+DROP INDEX IF EXISTS idx_propinsi_nm_trgm;
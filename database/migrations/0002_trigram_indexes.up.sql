@@ -0,0 +1,8 @@
+-- Trigram indexes for fuzzy name search (word_similarity / <% operator)
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_propinsi_nm_trgm ON propinsi USING GIN (nm_propinsi gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_kabupaten_nm_trgm ON kabupaten USING GIN (nm_kabupaten gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_kecamatan_nm_trgm ON kecamatan USING GIN (nm_kecamatan gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_kelurahan_nm_trgm ON kelurahan USING GIN (nm_kelurahan gin_trgm_ops);
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS trg_kelurahan_dataset_version ON kelurahan;
+DROP TRIGGER IF EXISTS trg_kecamatan_dataset_version ON kecamatan;
+DROP TRIGGER IF EXISTS trg_kabupaten_dataset_version ON kabupaten;
+DROP TRIGGER IF EXISTS trg_propinsi_dataset_version ON propinsi;
+DROP FUNCTION IF EXISTS bump_dataset_version();
+DROP TABLE IF EXISTS dataset_version;
@@ -0,0 +1,33 @@
+-- Dataset version for HTTP caching: every change to a region table bumps the
+-- version, which the API uses for ETag and Last-Modified headers.
+
+CREATE TABLE IF NOT EXISTS dataset_version (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    version BIGINT NOT NULL DEFAULT 1,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO dataset_version (id) VALUES (TRUE) ON CONFLICT (id) DO NOTHING;
+
+CREATE OR REPLACE FUNCTION bump_dataset_version() RETURNS trigger AS $$
+BEGIN
+    UPDATE dataset_version SET version = version + 1, updated_at = now();
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_propinsi_dataset_version ON propinsi;
+CREATE TRIGGER trg_propinsi_dataset_version AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON propinsi
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_dataset_version();
+
+DROP TRIGGER IF EXISTS trg_kabupaten_dataset_version ON kabupaten;
+CREATE TRIGGER trg_kabupaten_dataset_version AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON kabupaten
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_dataset_version();
+
+DROP TRIGGER IF EXISTS trg_kecamatan_dataset_version ON kecamatan;
+CREATE TRIGGER trg_kecamatan_dataset_version AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON kecamatan
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_dataset_version();
+
+DROP TRIGGER IF EXISTS trg_kelurahan_dataset_version ON kelurahan;
+CREATE TRIGGER trg_kelurahan_dataset_version AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON kelurahan
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_dataset_version();
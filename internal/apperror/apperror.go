@@ -0,0 +1,89 @@
+package apperror
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Code adalah kode error yang dikirim ke client dalam body response
+type Code string
+
+const (
+	CodeInvalidArgument Code = "INVALID_ARGUMENT"
+	CodeNotFound        Code = "NOT_FOUND"
+	CodePayloadTooLarge Code = "PAYLOAD_TOO_LARGE"
+	CodeTimeout         Code = "TIMEOUT"
+	CodeCanceled        Code = "CANCELED"
+	CodeUnavailable     Code = "UNAVAILABLE"
+	CodeInternal        Code = "INTERNAL"
+)
+
+// StatusClientClosedRequest adalah status non-standar (dari nginx) untuk request yang dibatalkan
+// client sebelum response selesai. Status ini bukan kegagalan server sehingga tidak dicatat sebagai error.
+const StatusClientClosedRequest = 499
+
+// Error adalah error bertipe yang dikembalikan repository dan handler.
+// Message aman untuk ditampilkan ke client, sedangkan Err menyimpan penyebab aslinya.
+type Error struct {
+	Code    Code
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// HTTPStatus mengembalikan HTTP status code yang sesuai dengan Code error
+func (e *Error) HTTPStatus() int {
+	switch e.Code {
+	case CodeInvalidArgument:
+		return http.StatusBadRequest
+	case CodeNotFound:
+		return http.StatusNotFound
+	case CodePayloadTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case CodeTimeout:
+		return http.StatusGatewayTimeout
+	case CodeCanceled:
+		return StatusClientClosedRequest
+	case CodeUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// New membuat Error baru tanpa penyebab
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Wrap membuat Error baru dengan penyebab err
+func Wrap(code Code, message string, err error) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+// InvalidArgument membuat Error untuk input request yang tidak valid
+func InvalidArgument(message string) *Error {
+	return New(CodeInvalidArgument, message)
+}
+
+// NotFound membuat Error untuk resource yang tidak ditemukan
+func NotFound(message string) *Error {
+	return New(CodeNotFound, message)
+}
+
+// As mengambil *Error dari rantai err, jika ada
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	ok := errors.As(err, &appErr)
+	return appErr, ok
+}
@@ -0,0 +1,219 @@
+// Package cache menyediakan cache in-memory LRU dengan TTL dan penggabungan request
+// (singleflight): beberapa miss bersamaan untuk key yang sama hanya memanggil loader sekali.
+package cache
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// errPanicked dikembalikan ke pemanggil yang menunggu jika loader panic
+var errPanicked = errors.New("cache: loader panicked")
+
+// Options mengatur batas cache. MaxEntries <= 0 menonaktifkan penyimpanan
+// (request bersamaan tetap digabung), TTL <= 0 berarti entry tidak pernah kedaluwarsa.
+// MaxBytes membatasi total ukuran nilai yang mengimplementasikan Sizer; <= 0 berarti tanpa batas.
+type Options struct {
+	MaxEntries int
+	MaxBytes   int64
+	TTL        time.Duration
+}
+
+// Sizer diimplementasikan nilai yang ukurannya dihitung terhadap Options.MaxBytes.
+// Nilai yang bertambah besar setelah disimpan harus melaporkannya lewat Cache.Resize.
+type Sizer interface {
+	Size() int64
+}
+
+// Stats adalah snapshot counter cache
+type Stats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Coalesced uint64 `json:"coalesced"`
+	Evictions uint64 `json:"evictions"`
+	Entries   int    `json:"entries"`
+	Bytes     int64  `json:"bytes"`
+}
+
+type entry[V any] struct {
+	key     string
+	value   V
+	size    int64
+	expires time.Time
+}
+
+// call adalah pemanggilan loader yang sedang berjalan untuk satu key
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// Cache menyimpan hasil loader per key. Nilai yang dikembalikan dipakai bersama
+// oleh semua pemanggil sehingga tidak boleh diubah.
+type Cache[V any] struct {
+	opts Options
+	now  func() time.Time
+
+	mu       sync.Mutex
+	lru      *list.List
+	items    map[string]*list.Element
+	inflight map[string]*call[V]
+	bytes    int64
+	// gen bertambah setiap Purge; hasil load yang dimulai sebelum Purge tidak disimpan
+	gen uint64
+
+	hits, misses, coalesced, evictions atomic.Uint64
+}
+
+// New membuat Cache kosong
+func New[V any](opts Options) *Cache[V] {
+	return &Cache[V]{
+		opts:     opts,
+		now:      time.Now,
+		lru:      list.New(),
+		items:    make(map[string]*list.Element),
+		inflight: make(map[string]*call[V]),
+	}
+}
+
+// Get mengembalikan nilai key dari cache, atau memanggil load jika belum ada atau sudah kedaluwarsa.
+// Jika load untuk key yang sama sedang berjalan, Get menunggu dan memakai hasilnya.
+// Error dari load dikembalikan ke semua pemanggil yang menunggu dan tidak disimpan.
+func (c *Cache[V]) Get(key string, load func() (V, error)) (V, error) {
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry[V])
+		if c.opts.TTL <= 0 || c.now().Before(e.expires) {
+			c.lru.MoveToFront(el)
+			c.mu.Unlock()
+			c.hits.Add(1)
+			return e.value, nil
+		}
+		c.remove(el)
+	}
+
+	if cl, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		c.coalesced.Add(1)
+		<-cl.done
+		return cl.value, cl.err
+	}
+
+	cl := &call[V]{done: make(chan struct{})}
+	c.inflight[key] = cl
+	gen := c.gen
+	c.mu.Unlock()
+	c.misses.Add(1)
+
+	// Panic di load tetap melepas pemanggil yang menunggu
+	defer func() {
+		c.mu.Lock()
+		if c.inflight[key] == cl {
+			delete(c.inflight, key)
+		}
+		if cl.err == nil && c.gen == gen {
+			c.store(key, cl.value)
+		}
+		c.mu.Unlock()
+		close(cl.done)
+	}()
+
+	cl.err = errPanicked
+	cl.value, cl.err = load()
+	return cl.value, cl.err
+}
+
+// Purge menghapus semua entry, mis. setelah data wilayah diperbarui. Load yang sedang berjalan
+// tetap selesai untuk pemanggilnya, tetapi hasilnya tidak disimpan dan tidak dipakai Get berikutnya.
+func (c *Cache[V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	c.inflight = make(map[string]*call[V])
+	c.lru.Init()
+	c.items = make(map[string]*list.Element)
+	c.bytes = 0
+}
+
+// Resize menghitung ulang ukuran entry key setelah nilainya bertambah besar dan menandainya
+// baru dipakai, lalu membuang entry yang paling lama tidak dipakai jika MaxBytes terlewati
+func (c *Cache[V]) Resize(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return
+	}
+	e := el.Value.(*entry[V])
+	size := sizeOf(e.value)
+	c.bytes += size - e.size
+	e.size = size
+	c.lru.MoveToFront(el)
+	c.evict()
+}
+
+// Stats mengembalikan counter cache saat ini
+func (c *Cache[V]) Stats() Stats {
+	c.mu.Lock()
+	entries, bytes := c.lru.Len(), c.bytes
+	c.mu.Unlock()
+
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Coalesced: c.coalesced.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   entries,
+		Bytes:     bytes,
+	}
+}
+
+// store menyimpan nilai dan membuang entry yang paling lama tidak dipakai jika cache penuh.
+// Harus dipanggil dengan c.mu terkunci.
+func (c *Cache[V]) store(key string, value V) {
+	if c.opts.MaxEntries <= 0 {
+		return
+	}
+
+	e := &entry[V]{key: key, value: value, size: sizeOf(value), expires: c.now().Add(c.opts.TTL)}
+	if el, ok := c.items[key]; ok {
+		c.bytes -= el.Value.(*entry[V]).size
+		el.Value = e
+		c.lru.MoveToFront(el)
+	} else {
+		c.items[key] = c.lru.PushFront(e)
+	}
+	c.bytes += e.size
+
+	c.evict()
+}
+
+// evict membuang entry yang paling lama tidak dipakai sampai MaxEntries dan MaxBytes terpenuhi.
+// Entry yang sendirian melebihi MaxBytes ikut dibuang. Harus dipanggil dengan c.mu terkunci.
+func (c *Cache[V]) evict() {
+	for c.lru.Len() > c.opts.MaxEntries || (c.opts.MaxBytes > 0 && c.bytes > c.opts.MaxBytes) {
+		c.remove(c.lru.Back())
+		c.evictions.Add(1)
+	}
+}
+
+func (c *Cache[V]) remove(el *list.Element) {
+	e := el.Value.(*entry[V])
+	c.lru.Remove(el)
+	delete(c.items, e.key)
+	c.bytes -= e.size
+}
+
+// sizeOf mengembalikan ukuran value jika mengimplementasikan Sizer, selain itu 0
+func sizeOf[V any](value V) int64 {
+	if s, ok := any(value).(Sizer); ok {
+		return s.Size()
+	}
+	return 0
+}
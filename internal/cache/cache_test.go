@@ -0,0 +1,211 @@
+package cache_test
+
+import (
+	"errors"
+	"location-svc/internal/cache"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetEvictsLeastRecentlyUsed(t *testing.T) {
+	c := cache.New[string](cache.Options{MaxEntries: 2})
+
+	var loads []string
+	get := func(key string) {
+		t.Helper()
+		v, err := c.Get(key, func() (string, error) {
+			loads = append(loads, key)
+			return "v" + key, nil
+		})
+		if err != nil || v != "v"+key {
+			t.Fatalf("Get(%q) = %q, %v", key, v, err)
+		}
+	}
+
+	get("a")
+	get("b")
+	get("a") // a menjadi yang terbaru, b dibuang saat c masuk
+	get("c")
+	get("a")
+	get("b")
+
+	if got := len(loads); got != 4 {
+		t.Errorf("loads = %v, want a, b, c, b", loads)
+	}
+	want := cache.Stats{Hits: 2, Misses: 4, Evictions: 2, Entries: 2}
+	if got := c.Stats(); got != want {
+		t.Errorf("Stats = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetExpires(t *testing.T) {
+	c := cache.New[int](cache.Options{MaxEntries: 10, TTL: 10 * time.Millisecond})
+
+	n := 0
+	load := func() (int, error) { n++; return n, nil }
+
+	c.Get("k", load)
+	if v, _ := c.Get("k", load); v != 1 {
+		t.Errorf("second Get = %d, want cached 1", v)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if v, _ := c.Get("k", load); v != 2 {
+		t.Errorf("Get after TTL = %d, want reloaded 2", v)
+	}
+}
+
+func TestGetDoesNotCacheErrors(t *testing.T) {
+	c := cache.New[int](cache.Options{MaxEntries: 10})
+	errNotFound := errors.New("not found")
+
+	if _, err := c.Get("k", func() (int, error) { return 0, errNotFound }); err != errNotFound {
+		t.Fatalf("err = %v, want %v", err, errNotFound)
+	}
+	if v, err := c.Get("k", func() (int, error) { return 7, nil }); err != nil || v != 7 {
+		t.Errorf("Get after error = %d, %v, want 7", v, err)
+	}
+}
+
+func TestGetCoalescesConcurrentMisses(t *testing.T) {
+	c := cache.New[string](cache.Options{MaxEntries: 10})
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func() (string, error) {
+		loads.Add(1)
+		<-release
+		return "feature", nil
+	}
+
+	const callers = 20
+	var wg sync.WaitGroup
+	results := make([]string, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = c.Get("32", load)
+		}(i)
+	}
+
+	// Tunggu sampai semua pemanggil selain loader sedang menunggu
+	for c.Stats().Coalesced+c.Stats().Hits < callers-1 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if n := loads.Load(); n != 1 {
+		t.Errorf("loader called %d times, want 1", n)
+	}
+	for i, r := range results {
+		if r != "feature" {
+			t.Errorf("results[%d] = %q", i, r)
+		}
+	}
+	if s := c.Stats(); s.Misses != 1 {
+		t.Errorf("Stats = %+v, want exactly one miss", s)
+	}
+}
+
+func TestGetDisabledStillCoalesces(t *testing.T) {
+	c := cache.New[string](cache.Options{})
+
+	for i := 0; i < 3; i++ {
+		v, _ := c.Get("k", func() (string, error) { return strconv.Itoa(i), nil })
+		if v != strconv.Itoa(i) {
+			t.Errorf("Get #%d = %q, want fresh value", i, v)
+		}
+	}
+	if s := c.Stats(); s.Entries != 0 || s.Misses != 3 {
+		t.Errorf("Stats = %+v, want 3 misses and nothing stored", s)
+	}
+}
+
+// sized adalah nilai dengan ukuran yang bisa bertambah setelah disimpan
+type sized struct{ n atomic.Int64 }
+
+func (s *sized) Size() int64 { return s.n.Load() }
+
+func newSized(n int64) *sized {
+	s := &sized{}
+	s.n.Store(n)
+	return s
+}
+
+func TestGetEvictsOverByteBudget(t *testing.T) {
+	c := cache.New[*sized](cache.Options{MaxEntries: 10, MaxBytes: 100})
+	load := func(n int64) func() (*sized, error) {
+		return func() (*sized, error) { return newSized(n), nil }
+	}
+
+	c.Get("a", load(40))
+	c.Get("b", load(40))
+	if got := c.Stats(); got.Entries != 2 || got.Bytes != 80 {
+		t.Fatalf("Stats = %+v, want 2 entries and 80 bytes", got)
+	}
+
+	// c melewati budget sehingga a, yang paling lama tidak dipakai, dibuang
+	c.Get("c", load(40))
+	if got := c.Stats(); got.Entries != 2 || got.Bytes != 80 || got.Evictions != 1 {
+		t.Fatalf("Stats = %+v, want a evicted", got)
+	}
+
+	// Entry yang sendirian melebihi budget tidak disimpan
+	c.Get("huge", load(500))
+	if got := c.Stats(); got.Entries != 0 || got.Bytes != 0 {
+		t.Errorf("Stats = %+v, want everything evicted", got)
+	}
+}
+
+func TestResizeEvictsGrownEntries(t *testing.T) {
+	c := cache.New[*sized](cache.Options{MaxEntries: 10, MaxBytes: 100})
+	a, _ := c.Get("a", func() (*sized, error) { return newSized(30), nil })
+	c.Get("b", func() (*sized, error) { return newSized(30), nil })
+
+	a.n.Store(60)
+	c.Resize("a")
+	if got := c.Stats(); got.Entries != 2 || got.Bytes != 90 {
+		t.Fatalf("Stats = %+v, want 90 bytes after resize", got)
+	}
+
+	a.n.Store(80)
+	c.Resize("a")
+	if got := c.Stats(); got.Entries != 1 || got.Bytes != 80 || got.Evictions != 1 {
+		t.Errorf("Stats = %+v, want b evicted to make room for a", got)
+	}
+}
+
+func TestPurgeDiscardsInflightLoad(t *testing.T) {
+	c := cache.New[string](cache.Options{MaxEntries: 10})
+
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan string)
+	go func() {
+		v, _ := c.Get("k", func() (string, error) {
+			close(started)
+			<-release
+			return "stale", nil
+		})
+		done <- v
+	}()
+
+	<-started
+	c.Purge()
+
+	// Get setelah Purge tidak menunggu load lama
+	if v, _ := c.Get("k", func() (string, error) { return "fresh", nil }); v != "fresh" {
+		t.Errorf("Get after Purge = %q, want fresh", v)
+	}
+
+	close(release)
+	if v := <-done; v != "stale" {
+		t.Errorf("in-flight Get = %q, want its own result", v)
+	}
+	if v, _ := c.Get("k", func() (string, error) { return "reloaded", nil }); v != "fresh" {
+		t.Errorf("cached value = %q, want fresh (load started before Purge must not be stored)", v)
+	}
+}
@@ -0,0 +1,139 @@
+// Package compress menyediakan kompresi response gzip dan brotli yang dinegosiasikan dari
+// header Accept-Encoding, baik sebagai middleware Echo maupun untuk body yang dikompresi sekali lalu di-cache.
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Content-Encoding yang didukung; Identity berarti tanpa kompresi
+const (
+	Brotli   = "br"
+	Gzip     = "gzip"
+	Identity = ""
+)
+
+// Level kompresi: response dinamis memakai level cepat, body yang di-cache memakai level
+// tinggi karena hanya dikompresi sekali
+const (
+	dynamicGzipLevel   = gzip.DefaultCompression
+	dynamicBrotliLevel = 4
+	staticGzipLevel    = gzip.BestCompression
+	staticBrotliLevel  = 9
+)
+
+// Negotiate memilih encoding dari header Accept-Encoding berdasarkan q-value.
+// Jika bobotnya sama brotli diutamakan karena hasilnya lebih kecil.
+func Negotiate(acceptEncoding string) string {
+	weights := map[string]float64{}
+	wildcard := -1.0
+
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		if name == "*" {
+			wildcard = q
+		} else {
+			weights[name] = q
+		}
+	}
+
+	best, bestQ := Identity, 0.0
+	for _, encoding := range []string{Brotli, Gzip} {
+		q, ok := weights[encoding]
+		if !ok {
+			q = wildcard
+		}
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
+
+// Encode mengompresi b sekaligus dengan level tinggi, untuk body yang disimpan di cache
+func Encode(encoding string, b []byte) ([]byte, error) {
+	if encoding == Identity {
+		return b, nil
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(b) / 4)
+
+	var w io.WriteCloser
+	switch encoding {
+	case Brotli:
+		w = brotli.NewWriterLevel(&buf, staticBrotliLevel)
+	case Gzip:
+		w, _ = gzip.NewWriterLevel(&buf, staticGzipLevel)
+	default:
+		return nil, fmt.Errorf("compress: unsupported encoding %q", encoding)
+	}
+
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encoder adalah writer kompresi yang bisa di-reset dan dipakai ulang
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// Writer gzip/brotli dipakai ulang antar request karena alokasi state-nya besar
+var (
+	gzipPool = sync.Pool{New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(io.Discard, dynamicGzipLevel)
+		return w
+	}}
+	brotliPool = sync.Pool{New: func() interface{} {
+		return brotli.NewWriterLevel(io.Discard, dynamicBrotliLevel)
+	}}
+)
+
+func acquireEncoder(encoding string, w io.Writer) encoder {
+	var enc encoder
+	if encoding == Brotli {
+		enc = brotliPool.Get().(*brotli.Writer)
+	} else {
+		enc = gzipPool.Get().(*gzip.Writer)
+	}
+	enc.Reset(w)
+	return enc
+}
+
+func releaseEncoder(encoding string, enc encoder) {
+	enc.Reset(io.Discard)
+	if encoding == Brotli {
+		brotliPool.Put(enc)
+	} else {
+		gzipPool.Put(enc)
+	}
+}
@@ -0,0 +1,138 @@
+package compress_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"location-svc/internal/compress"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/labstack/echo/v4"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", compress.Identity},
+		{"gzip", compress.Gzip},
+		{"gzip, deflate, br", compress.Brotli},
+		{"br;q=0.5, gzip", compress.Gzip},
+		{"br;q=0, gzip;q=0", compress.Identity},
+		{"*", compress.Brotli},
+		{"gzip;q=0.2, *;q=0.5", compress.Brotli},
+		{"identity, deflate", compress.Identity},
+		{"GZIP;q=invalid, br", compress.Brotli},
+	}
+
+	for _, tt := range tests {
+		if got := compress.Negotiate(tt.accept); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func decompress(t *testing.T, encoding string, b []byte) string {
+	t.Helper()
+
+	var r io.Reader
+	switch encoding {
+	case compress.Gzip:
+		gr, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = gr
+	case compress.Brotli:
+		r = brotli.NewReader(bytes.NewReader(b))
+	default:
+		return string(b)
+	}
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestEncode(t *testing.T) {
+	body := strings.Repeat(`[106.8,-6.2],`, 1000)
+	for _, encoding := range []string{compress.Gzip, compress.Brotli} {
+		b, err := compress.Encode(encoding, []byte(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) >= len(body)/10 || decompress(t, encoding, b) != body {
+			t.Errorf("%s: %d bytes, want round trip under %d", encoding, len(b), len(body)/10)
+		}
+	}
+	if _, err := compress.Encode("deflate", nil); err == nil {
+		t.Error("Encode(deflate) succeeded, want error")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	large := strings.Repeat("kelurahan ", 500)
+
+	e := echo.New()
+	e.Use(compress.Middleware(1024))
+	e.GET("/large", func(c echo.Context) error { return c.String(http.StatusOK, large) })
+	e.GET("/small", func(c echo.Context) error { return c.String(http.StatusOK, "ok") })
+	e.GET("/precompressed", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentEncoding, compress.Gzip)
+		return c.Blob(http.StatusOK, echo.MIMEOctetStream, []byte(large))
+	})
+	e.GET("/not-modified", func(c echo.Context) error { return c.NoContent(http.StatusNotModified) })
+	e.GET("/stream", func(c echo.Context) error {
+		for i := 0; i < 3; i++ {
+			c.Response().Write([]byte("chunk "))
+			c.Response().Flush()
+		}
+		return nil
+	})
+
+	tests := []struct {
+		target   string
+		accept   string
+		encoding string
+		body     string
+	}{
+		{"/large", "gzip", compress.Gzip, large},
+		{"/large", "br, gzip", compress.Brotli, large},
+		{"/large", "", compress.Identity, large},
+		{"/small", "br", compress.Identity, "ok"},
+		{"/precompressed", "br", compress.Gzip, large},
+		{"/not-modified", "br", compress.Identity, ""},
+		{"/stream", "gzip", compress.Gzip, "chunk chunk chunk "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target+" "+tt.accept, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Header.Set(echo.HeaderAcceptEncoding, tt.accept)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get(echo.HeaderContentEncoding); got != tt.encoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.encoding)
+			}
+			if rec.Header().Get(echo.HeaderVary) != echo.HeaderAcceptEncoding {
+				t.Errorf("Vary = %q", rec.Header().Get(echo.HeaderVary))
+			}
+
+			body := rec.Body.Bytes()
+			if tt.target != "/precompressed" {
+				body = []byte(decompress(t, tt.encoding, body))
+			}
+			if string(body) != tt.body {
+				t.Errorf("body = %.50q, want %.50q", body, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package compress
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware mengompresi response dengan encoding hasil Negotiate. Response yang lebih kecil
+// dari minSize, response tanpa body (204, 304, HEAD) dan response yang sudah membawa
+// Content-Encoding (mis. body terkompresi dari cache) dikirim apa adanya.
+func Middleware(minSize int) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			res := c.Response()
+			res.Header().Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+
+			encoding := Negotiate(c.Request().Header.Get(echo.HeaderAcceptEncoding))
+			if encoding == Identity || c.Request().Method == http.MethodHead {
+				return next(c)
+			}
+
+			w := &responseWriter{ResponseWriter: res.Writer, encoding: encoding, minSize: minSize}
+			res.Writer = w
+			defer func() {
+				w.close()
+				res.Writer = w.ResponseWriter
+			}()
+
+			return next(c)
+		}
+	}
+}
+
+// responseWriter menahan body sampai minSize byte untuk memutuskan apakah response dikompresi
+type responseWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status  int
+	buf     []byte
+	decided bool
+	enc     encoder
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	w.status = status
+
+	header := w.Header()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified ||
+		header.Get(echo.HeaderContentEncoding) != "" {
+		w.decide(false)
+	}
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if !w.decided {
+		w.buf = append(w.buf, b...)
+		if len(w.buf) < w.minSize {
+			return len(b), nil
+		}
+		buffered := w.buf
+		w.buf = nil
+		if err := w.start(buffered); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+
+	if w.enc != nil {
+		return w.enc.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// start mengirim header dan memulai kompresi dengan body yang sudah tertahan
+func (w *responseWriter) start(buffered []byte) error {
+	w.decide(true)
+	_, err := w.enc.Write(buffered)
+	return err
+}
+
+// decide mengirim header response; compress menentukan apakah body melewati encoder
+func (w *responseWriter) decide(compress bool) {
+	if w.decided {
+		return
+	}
+	w.decided = true
+
+	if compress {
+		header := w.Header()
+		header.Set(echo.HeaderContentEncoding, w.encoding)
+		header.Del(echo.HeaderContentLength)
+		w.enc = acquireEncoder(w.encoding, w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// Flush mengirim data yang tertahan ke client, dipakai response streaming seperti export
+func (w *responseWriter) Flush() {
+	if !w.decided && w.status != 0 {
+		buffered := w.buf
+		w.buf = nil
+		w.start(buffered)
+	}
+	if w.enc != nil {
+		w.enc.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack diteruskan agar koneksi websocket/upgrade tetap berfungsi
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// close menyelesaikan response: body kecil dikirim tanpa kompresi, encoder ditutup dan dikembalikan ke pool
+func (w *responseWriter) close() {
+	if !w.decided {
+		if w.status == 0 {
+			// Handler tidak menulis apa pun
+			return
+		}
+		w.decide(false)
+		w.ResponseWriter.Write(w.buf)
+		return
+	}
+
+	if w.enc != nil {
+		w.enc.Close()
+		releaseEncoder(w.encoding, w.enc)
+		w.enc = nil
+	}
+}
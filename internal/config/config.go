@@ -0,0 +1,395 @@
+// Package config memuat konfigurasi service dari file .env, environment variable dan flag
+// command line menjadi satu struct bertipe yang sudah divalidasi.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Config adalah seluruh konfigurasi service
+type Config struct {
+	// Env adalah nama environment (mis. development, production)
+	Env string
+
+	Server ServerConfig
+	DB     DBConfig
+	CORS   CORSConfig
+	Cache  CacheConfig
+	Limits LimitsConfig
+	Log    LogConfig
+	Health HealthConfig
+}
+
+// ServerConfig berisi konfigurasi server HTTP
+type ServerConfig struct {
+	Port int
+	// CompressionMinSize adalah ukuran minimum response (byte) yang dikompresi gzip/brotli
+	CompressionMinSize int
+	// ShutdownDelay adalah jeda antara readiness gagal dan server berhenti menerima koneksi,
+	// agar load balancer sempat mengeluarkan instance dari rotasi
+	ShutdownDelay time.Duration
+	// ShutdownTimeout adalah batas waktu menunggu request yang sedang berjalan selesai
+	ShutdownTimeout time.Duration
+}
+
+// Addr mengembalikan alamat listen server HTTP
+func (s ServerConfig) Addr() string {
+	return ":" + strconv.Itoa(s.Port)
+}
+
+// DBConfig berisi koneksi dan pool database
+type DBConfig struct {
+	// URL adalah primary; migrate, import dan pemeriksaan readiness selalu memakai primary
+	URL string
+	// ReplicaURLs adalah read replica untuk query baca API; kosong berarti semua query ke primary
+	ReplicaURLs []string
+	// MaxOpenConns 0 berarti tanpa batas
+	MaxOpenConns int
+	MaxIdleConns int
+	// ConnMaxLifetime dan ConnMaxIdleTime 0 berarti koneksi tidak pernah ditutup karena umur/idle
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// ConnectTimeout adalah batas waktu percobaan ulang koneksi saat startup; 0 berarti satu percobaan
+	ConnectTimeout time.Duration
+	// HealthCheckInterval adalah jarak ping berkala untuk mencatat koneksi putus/pulih; 0 menonaktifkan
+	HealthCheckInterval time.Duration
+}
+
+// CORSConfig berisi origin yang diizinkan mengakses API dari browser
+type CORSConfig struct {
+	AllowOrigins []string
+}
+
+// CacheConfig berisi cache GeoJSON in-memory dan header Cache-Control
+type CacheConfig struct {
+	// GeoJSONSize adalah jumlah maksimum entry cache GeoJSON, 0 menonaktifkan cache
+	GeoJSONSize int
+	// GeoJSONMaxMB adalah batas memori cache GeoJSON (geometry dan body terkompresi), 0 berarti tanpa batas
+	GeoJSONMaxMB int
+	GeoJSONTTL   time.Duration
+	Control      CacheControl
+}
+
+// CacheControl berisi nilai header Cache-Control per grup endpoint; string kosong berarti tanpa header
+type CacheControl struct {
+	Search  string
+	GeoJSON string
+	Tiles   string
+	Export  string
+	Reverse string
+}
+
+// LimitsConfig berisi batas waktu query database per grup endpoint; 0 berarti tanpa batas
+type LimitsConfig struct {
+	SearchTimeout  time.Duration
+	GeoJSONTimeout time.Duration
+	TilesTimeout   time.Duration
+	ReverseTimeout time.Duration
+	// ExportTimeout berlaku untuk seluruh stream export
+	ExportTimeout time.Duration
+}
+
+// LogConfig berisi level log dan ambang log query lambat
+type LogConfig struct {
+	Level slog.Level
+	// SlowQueryThreshold 0 menonaktifkan log query lambat
+	SlowQueryThreshold time.Duration
+}
+
+// HealthConfig berisi pemeriksaan readiness /readyz
+type HealthConfig struct {
+	// ReadyTimeout adalah batas waktu setiap pemeriksaan readiness; 0 berarti tanpa batas
+	ReadyTimeout time.Duration
+	// MinRows adalah jumlah baris minimum per level agar service dianggap siap
+	MinRows LevelRows
+}
+
+// LevelRows berisi jumlah baris per level wilayah
+type LevelRows struct {
+	Propinsi  int
+	Kabupaten int
+	Kecamatan int
+	Kelurahan int
+}
+
+// Development melaporkan apakah service berjalan di environment development
+func (c *Config) Development() bool {
+	return strings.EqualFold(c.Env, "development")
+}
+
+// Default mengembalikan konfigurasi default. DB.URL sengaja kosong karena wajib di-set.
+func Default() *Config {
+	return &Config{
+		Env: "production",
+		Server: ServerConfig{
+			Port:               8080,
+			CompressionMinSize: 1024,
+			ShutdownDelay:      5 * time.Second,
+			ShutdownTimeout:    30 * time.Second,
+		},
+		DB: DBConfig{
+			MaxOpenConns:        25,
+			MaxIdleConns:        25,
+			ConnMaxLifetime:     30 * time.Minute,
+			ConnMaxIdleTime:     5 * time.Minute,
+			ConnectTimeout:      time.Minute,
+			HealthCheckInterval: 15 * time.Second,
+		},
+		CORS: CORSConfig{
+			AllowOrigins: []string{"*"},
+		},
+		Cache: CacheConfig{
+			GeoJSONSize:  1000,
+			GeoJSONMaxMB: 256,
+			GeoJSONTTL:   time.Hour,
+			// Batas wilayah jarang berubah sehingga GeoJSON dan tiles boleh di-cache lama,
+			// hasil pencarian lebih singkat
+			Control: CacheControl{
+				Search:  "public, max-age=300",
+				GeoJSON: "public, max-age=86400",
+				Tiles:   "public, max-age=86400",
+				Export:  "public, max-age=3600",
+				Reverse: "public, max-age=3600",
+			},
+		},
+		Limits: LimitsConfig{
+			SearchTimeout:  5 * time.Second,
+			GeoJSONTimeout: 15 * time.Second,
+			TilesTimeout:   10 * time.Second,
+			ReverseTimeout: 5 * time.Second,
+			ExportTimeout:  10 * time.Minute,
+		},
+		Log: LogConfig{
+			Level:              slog.LevelInfo,
+			SlowQueryThreshold: 500 * time.Millisecond,
+		},
+		Health: HealthConfig{
+			ReadyTimeout: 2 * time.Second,
+			MinRows:      LevelRows{Propinsi: 1, Kabupaten: 1, Kecamatan: 1, Kelurahan: 1},
+		},
+	}
+}
+
+// Load membaca konfigurasi dengan prioritas: flag, environment variable, file env (-env-file,
+// default .env; environment variable yang sudah ada tidak ditimpa), lalu Default.
+// Semua nilai yang tidak valid dilaporkan sekaligus.
+func Load(args []string) (*Config, error) {
+	flags := flag.NewFlagSet("location-svc", flag.ContinueOnError)
+	envFile := flags.String("env-file", ".env", "file with environment variables to load")
+	port := flags.Int("port", 0, "HTTP port (overrides PORT)")
+	databaseURL := flags.String("database-url", "", "PostgreSQL connection string (overrides DATABASE_URL)")
+	logLevel := flags.String("log-level", "", "debug, info, warn or error (overrides LOG_LEVEL)")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() != 0 {
+		return nil, fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	set := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	// File .env default boleh tidak ada, file yang disebut eksplisit wajib ada
+	if err := godotenv.Load(*envFile); err != nil && (set["env-file"] || !errors.Is(err, fs.ErrNotExist)) {
+		return nil, fmt.Errorf("load %s: %w", *envFile, err)
+	}
+
+	cfg := Default()
+	env := envLoader{lookup: os.LookupEnv}
+
+	env.string("ENV", &cfg.Env)
+
+	env.int("PORT", &cfg.Server.Port)
+	env.int("COMPRESSION_MIN_SIZE", &cfg.Server.CompressionMinSize)
+	env.duration("SHUTDOWN_DELAY", &cfg.Server.ShutdownDelay)
+	env.duration("SHUTDOWN_TIMEOUT", &cfg.Server.ShutdownTimeout)
+
+	env.string("DATABASE_URL", &cfg.DB.URL)
+	env.list("DATABASE_REPLICA_URLS", &cfg.DB.ReplicaURLs)
+	env.int("DB_MAX_OPEN_CONNS", &cfg.DB.MaxOpenConns)
+	env.int("DB_MAX_IDLE_CONNS", &cfg.DB.MaxIdleConns)
+	env.duration("DB_CONN_MAX_LIFETIME", &cfg.DB.ConnMaxLifetime)
+	env.duration("DB_CONN_MAX_IDLE_TIME", &cfg.DB.ConnMaxIdleTime)
+	env.duration("DB_CONNECT_TIMEOUT", &cfg.DB.ConnectTimeout)
+	env.duration("DB_HEALTH_CHECK_INTERVAL", &cfg.DB.HealthCheckInterval)
+
+	env.list("CORS_ALLOW_ORIGINS", &cfg.CORS.AllowOrigins)
+
+	env.int("GEOJSON_CACHE_SIZE", &cfg.Cache.GeoJSONSize)
+	env.int("GEOJSON_CACHE_MAX_MB", &cfg.Cache.GeoJSONMaxMB)
+	env.duration("GEOJSON_CACHE_TTL", &cfg.Cache.GeoJSONTTL)
+	env.string("CACHE_CONTROL_SEARCH", &cfg.Cache.Control.Search)
+	env.string("CACHE_CONTROL_GEOJSON", &cfg.Cache.Control.GeoJSON)
+	env.string("CACHE_CONTROL_TILES", &cfg.Cache.Control.Tiles)
+	env.string("CACHE_CONTROL_EXPORT", &cfg.Cache.Control.Export)
+	env.string("CACHE_CONTROL_REVERSE", &cfg.Cache.Control.Reverse)
+
+	env.duration("QUERY_TIMEOUT_SEARCH", &cfg.Limits.SearchTimeout)
+	env.duration("QUERY_TIMEOUT_GEOJSON", &cfg.Limits.GeoJSONTimeout)
+	env.duration("QUERY_TIMEOUT_TILES", &cfg.Limits.TilesTimeout)
+	env.duration("QUERY_TIMEOUT_REVERSE", &cfg.Limits.ReverseTimeout)
+	env.duration("QUERY_TIMEOUT_EXPORT", &cfg.Limits.ExportTimeout)
+
+	env.level("LOG_LEVEL", &cfg.Log.Level)
+	env.duration("SLOW_QUERY_THRESHOLD", &cfg.Log.SlowQueryThreshold)
+
+	env.duration("READYZ_TIMEOUT", &cfg.Health.ReadyTimeout)
+	env.int("READYZ_MIN_ROWS_PROPINSI", &cfg.Health.MinRows.Propinsi)
+	env.int("READYZ_MIN_ROWS_KABUPATEN", &cfg.Health.MinRows.Kabupaten)
+	env.int("READYZ_MIN_ROWS_KECAMATAN", &cfg.Health.MinRows.Kecamatan)
+	env.int("READYZ_MIN_ROWS_KELURAHAN", &cfg.Health.MinRows.Kelurahan)
+
+	if set["port"] {
+		cfg.Server.Port = *port
+	}
+	if set["database-url"] {
+		cfg.DB.URL = *databaseURL
+	}
+	if set["log-level"] {
+		if err := parseLevel(*logLevel, &cfg.Log.Level); err != nil {
+			env.errs = append(env.errs, fmt.Errorf("-log-level: %w", err))
+		}
+	}
+
+	if err := errors.Join(append(env.errs, cfg.Validate())...); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate memeriksa nilai yang tidak bisa dipakai service
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "PORT must be between 1 and 65535, got %d", c.Server.Port)
+	check(c.Server.CompressionMinSize >= 0, "COMPRESSION_MIN_SIZE must not be negative")
+	check(c.Server.ShutdownDelay >= 0, "SHUTDOWN_DELAY must not be negative")
+	check(c.Server.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT must be positive")
+
+	check(c.DB.URL != "", "DATABASE_URL is required")
+	check(c.DB.MaxOpenConns >= 0, "DB_MAX_OPEN_CONNS must not be negative")
+	check(c.DB.MaxIdleConns >= 0, "DB_MAX_IDLE_CONNS must not be negative")
+	check(c.DB.MaxOpenConns == 0 || c.DB.MaxIdleConns <= c.DB.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS (%d) must not exceed DB_MAX_OPEN_CONNS (%d)", c.DB.MaxIdleConns, c.DB.MaxOpenConns)
+	check(c.DB.ConnMaxLifetime >= 0, "DB_CONN_MAX_LIFETIME must not be negative")
+	check(c.DB.ConnMaxIdleTime >= 0, "DB_CONN_MAX_IDLE_TIME must not be negative")
+	check(c.DB.ConnectTimeout >= 0, "DB_CONNECT_TIMEOUT must not be negative")
+	check(c.DB.HealthCheckInterval >= 0, "DB_HEALTH_CHECK_INTERVAL must not be negative")
+
+	check(len(c.CORS.AllowOrigins) > 0, "CORS_ALLOW_ORIGINS must contain at least one origin")
+
+	check(c.Cache.GeoJSONSize >= 0, "GEOJSON_CACHE_SIZE must not be negative")
+	check(c.Cache.GeoJSONMaxMB >= 0, "GEOJSON_CACHE_MAX_MB must not be negative")
+	check(c.Cache.GeoJSONTTL >= 0, "GEOJSON_CACHE_TTL must not be negative")
+
+	check(c.Limits.SearchTimeout >= 0, "QUERY_TIMEOUT_SEARCH must not be negative")
+	check(c.Limits.GeoJSONTimeout >= 0, "QUERY_TIMEOUT_GEOJSON must not be negative")
+	check(c.Limits.TilesTimeout >= 0, "QUERY_TIMEOUT_TILES must not be negative")
+	check(c.Limits.ReverseTimeout >= 0, "QUERY_TIMEOUT_REVERSE must not be negative")
+	check(c.Limits.ExportTimeout >= 0, "QUERY_TIMEOUT_EXPORT must not be negative")
+	check(c.Log.SlowQueryThreshold >= 0, "SLOW_QUERY_THRESHOLD must not be negative")
+
+	check(c.Health.ReadyTimeout >= 0, "READYZ_TIMEOUT must not be negative")
+	check(c.Health.MinRows.Propinsi >= 0, "READYZ_MIN_ROWS_PROPINSI must not be negative")
+	check(c.Health.MinRows.Kabupaten >= 0, "READYZ_MIN_ROWS_KABUPATEN must not be negative")
+	check(c.Health.MinRows.Kecamatan >= 0, "READYZ_MIN_ROWS_KECAMATAN must not be negative")
+	check(c.Health.MinRows.Kelurahan >= 0, "READYZ_MIN_ROWS_KELURAHAN must not be negative")
+
+	return errors.Join(errs...)
+}
+
+// envLoader membaca environment variable ke field Config dan mengumpulkan semua error parsing
+type envLoader struct {
+	lookup func(string) (string, bool)
+	errs   []error
+}
+
+// string membaca nilai apa adanya; variable yang di-set kosong tetap dipakai (mis. CACHE_CONTROL_*)
+func (l *envLoader) string(key string, dst *string) {
+	if v, ok := l.lookup(key); ok {
+		*dst = v
+	}
+}
+
+func (l *envLoader) int(key string, dst *int) {
+	v, ok := l.lookup(key)
+	if !ok || v == "" {
+		return
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: invalid integer %q", key, v))
+		return
+	}
+	*dst = n
+}
+
+func (l *envLoader) duration(key string, dst *time.Duration) {
+	v, ok := l.lookup(key)
+	if !ok || v == "" {
+		return
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: invalid duration %q (e.g. 500ms, 30s, 1h)", key, v))
+		return
+	}
+	*dst = d
+}
+
+// list membaca daftar yang dipisahkan koma
+func (l *envLoader) list(key string, dst *[]string) {
+	v, ok := l.lookup(key)
+	if !ok {
+		return
+	}
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*dst = items
+}
+
+func (l *envLoader) level(key string, dst *slog.Level) {
+	v, ok := l.lookup(key)
+	if !ok || v == "" {
+		return
+	}
+	if err := parseLevel(v, dst); err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: %w", key, err))
+	}
+}
+
+// parseLevel mengubah nama level (debug, info, warn/warning, error) menjadi slog.Level
+func parseLevel(level string, dst *slog.Level) error {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		*dst = slog.LevelDebug
+	case "info":
+		*dst = slog.LevelInfo
+	case "warn", "warning":
+		*dst = slog.LevelWarn
+	case "error":
+		*dst = slog.LevelError
+	default:
+		return fmt.Errorf("invalid level %q: want debug, info, warn or error", level)
+	}
+	return nil
+}
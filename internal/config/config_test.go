@@ -0,0 +1,176 @@
+package config_test
+
+import (
+	"location-svc/internal/config"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// configKeys adalah semua environment variable yang dibaca config.Load
+var configKeys = []string{
+	"ENV", "PORT", "COMPRESSION_MIN_SIZE", "SHUTDOWN_DELAY", "SHUTDOWN_TIMEOUT",
+	"DATABASE_URL", "DATABASE_REPLICA_URLS", "DB_MAX_OPEN_CONNS", "DB_MAX_IDLE_CONNS", "DB_CONN_MAX_LIFETIME",
+	"DB_CONN_MAX_IDLE_TIME", "DB_CONNECT_TIMEOUT", "DB_HEALTH_CHECK_INTERVAL",
+	"CORS_ALLOW_ORIGINS", "GEOJSON_CACHE_SIZE", "GEOJSON_CACHE_MAX_MB", "GEOJSON_CACHE_TTL",
+	"CACHE_CONTROL_SEARCH", "CACHE_CONTROL_GEOJSON", "CACHE_CONTROL_TILES", "CACHE_CONTROL_EXPORT", "CACHE_CONTROL_REVERSE",
+	"QUERY_TIMEOUT_SEARCH", "QUERY_TIMEOUT_GEOJSON", "QUERY_TIMEOUT_TILES", "QUERY_TIMEOUT_REVERSE", "QUERY_TIMEOUT_EXPORT",
+	"LOG_LEVEL", "SLOW_QUERY_THRESHOLD", "READYZ_TIMEOUT",
+	"READYZ_MIN_ROWS_PROPINSI", "READYZ_MIN_ROWS_KABUPATEN", "READYZ_MIN_ROWS_KECAMATAN", "READYZ_MIN_ROWS_KELURAHAN",
+}
+
+// setEnv mengosongkan environment config lalu men-set env; nilai asli dipulihkan setelah test
+func setEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, key := range configKeys {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+}
+
+const testDatabaseURL = "postgres://user:secret@db:5432/location?sslmode=disable"
+
+func TestLoadDefaults(t *testing.T) {
+	setEnv(t, map[string]string{"DATABASE_URL": testDatabaseURL})
+
+	cfg, err := config.Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := config.Default()
+	want.DB.URL = testDatabaseURL
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Load() = %+v, want %+v", cfg, want)
+	}
+	if cfg.Server.Addr() != ":8080" || cfg.Development() {
+		t.Errorf("Addr() = %q, Development() = %v", cfg.Server.Addr(), cfg.Development())
+	}
+}
+
+func TestLoadEnv(t *testing.T) {
+	setEnv(t, map[string]string{
+		"ENV":                       "development",
+		"PORT":                      "9090",
+		"DATABASE_URL":              testDatabaseURL,
+		"DATABASE_REPLICA_URLS":     "postgres://replica1/location, postgres://replica2/location",
+		"DB_MAX_OPEN_CONNS":         "10",
+		"DB_MAX_IDLE_CONNS":         "5",
+		"CORS_ALLOW_ORIGINS":        "https://a.example, https://b.example",
+		"GEOJSON_CACHE_SIZE":        "0",
+		"CACHE_CONTROL_SEARCH":      "",
+		"QUERY_TIMEOUT_EXPORT":      "1h",
+		"LOG_LEVEL":                 "WARNING",
+		"SLOW_QUERY_THRESHOLD":      "0",
+		"QUERY_TIMEOUT_GEOJSON":     "2s",
+		"READYZ_MIN_ROWS_PROPINSI":  "34",
+		"READYZ_MIN_ROWS_KELURAHAN": "80000",
+	})
+
+	cfg, err := config.Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !cfg.Development() || cfg.Server.Port != 9090 {
+		t.Errorf("Env = %q, Port = %d", cfg.Env, cfg.Server.Port)
+	}
+	if !reflect.DeepEqual(cfg.DB.ReplicaURLs, []string{"postgres://replica1/location", "postgres://replica2/location"}) {
+		t.Errorf("ReplicaURLs = %q", cfg.DB.ReplicaURLs)
+	}
+	if cfg.DB.MaxOpenConns != 10 || cfg.DB.MaxIdleConns != 5 {
+		t.Errorf("pool = %d/%d, want 10/5", cfg.DB.MaxOpenConns, cfg.DB.MaxIdleConns)
+	}
+	if !reflect.DeepEqual(cfg.CORS.AllowOrigins, []string{"https://a.example", "https://b.example"}) {
+		t.Errorf("AllowOrigins = %q", cfg.CORS.AllowOrigins)
+	}
+	if cfg.Cache.GeoJSONSize != 0 || cfg.Cache.Control.Search != "" || cfg.Cache.Control.GeoJSON == "" {
+		t.Errorf("Cache = %+v", cfg.Cache)
+	}
+	if cfg.Limits.ExportTimeout != time.Hour || cfg.Limits.GeoJSONTimeout != 2*time.Second {
+		t.Errorf("Limits = %+v", cfg.Limits)
+	}
+	if cfg.Log.Level != slog.LevelWarn || cfg.Log.SlowQueryThreshold != 0 {
+		t.Errorf("Log = %+v", cfg.Log)
+	}
+	if want := (config.LevelRows{Propinsi: 34, Kabupaten: 1, Kecamatan: 1, Kelurahan: 80000}); cfg.Health.MinRows != want {
+		t.Errorf("MinRows = %+v, want %+v", cfg.Health.MinRows, want)
+	}
+}
+
+func TestLoadFlagsOverrideEnv(t *testing.T) {
+	setEnv(t, map[string]string{"DATABASE_URL": "postgres://env", "PORT": "9090", "LOG_LEVEL": "error"})
+
+	cfg, err := config.Load([]string{"-port", "7070", "-database-url", testDatabaseURL, "-log-level", "debug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Port != 7070 || cfg.DB.URL != testDatabaseURL || cfg.Log.Level != slog.LevelDebug {
+		t.Errorf("Port = %d, URL = %q, Level = %v", cfg.Server.Port, cfg.DB.URL, cfg.Log.Level)
+	}
+}
+
+func TestLoadEnvFile(t *testing.T) {
+	setEnv(t, map[string]string{"PORT": "9090"})
+
+	path := filepath.Join(t.TempDir(), "test.env")
+	content := "DATABASE_URL=" + testDatabaseURL + "\nPORT=7070\nGEOJSON_CACHE_TTL=5m\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load([]string{"-env-file", path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Environment variable yang sudah di-set tidak ditimpa file
+	if cfg.DB.URL != testDatabaseURL || cfg.Server.Port != 9090 || cfg.Cache.GeoJSONTTL != 5*time.Minute {
+		t.Errorf("URL = %q, Port = %d, TTL = %s", cfg.DB.URL, cfg.Server.Port, cfg.Cache.GeoJSONTTL)
+	}
+
+	if _, err := config.Load([]string{"-env-file", filepath.Join(t.TempDir(), "missing.env")}); err == nil {
+		t.Error("missing explicit env file should fail")
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	setEnv(t, map[string]string{
+		"PORT":                 "http",
+		"DB_MAX_OPEN_CONNS":    "5",
+		"DB_MAX_IDLE_CONNS":    "10",
+		"GEOJSON_CACHE_TTL":    "forever",
+		"QUERY_TIMEOUT_SEARCH": "-1s",
+		"LOG_LEVEL":            "verbose",
+		"CORS_ALLOW_ORIGINS":   " , ",
+	})
+
+	_, err := config.Load(nil)
+	if err == nil {
+		t.Fatal("Load() succeeded, want error")
+	}
+
+	// Semua kesalahan dilaporkan sekaligus
+	for _, want := range []string{
+		"PORT", "DATABASE_URL is required", "DB_MAX_IDLE_CONNS (10) must not exceed DB_MAX_OPEN_CONNS (5)",
+		"GEOJSON_CACHE_TTL", "QUERY_TIMEOUT_SEARCH", "LOG_LEVEL", "CORS_ALLOW_ORIGINS",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestLoadUnexpectedArgument(t *testing.T) {
+	setEnv(t, map[string]string{"DATABASE_URL": testDatabaseURL})
+
+	if _, err := config.Load([]string{"serve"}); err == nil {
+		t.Error("unexpected positional argument should fail")
+	}
+}
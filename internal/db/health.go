@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"location-svc/internal/config"
+	"location-svc/internal/health"
+	"location-svc/internal/migrate"
+
+	"github.com/lib/pq"
+)
+
+// levelMin adalah tabel wilayah satu level beserta jumlah baris minimumnya
+type levelMin struct {
+	table string
+	min   int
+}
+
+// ReadinessChecks mengembalikan pemeriksaan readiness untuk /readyz: ping database, versi PostGIS,
+// jumlah baris minimal per level dan versi migrasi yang diterapkan
+func ReadinessChecks(db *sql.DB, minRows config.LevelRows) ([]health.Check, error) {
+	migrator, err := migrate.New(db)
+	if err != nil {
+		return nil, err
+	}
+
+	return []health.Check{
+		{Name: "database", Run: func(ctx context.Context) (interface{}, error) {
+			stats := db.Stats()
+			detail := map[string]int{
+				"open_connections": stats.OpenConnections,
+				"in_use":           stats.InUse,
+				"idle":             stats.Idle,
+			}
+			return detail, db.PingContext(ctx)
+		}},
+		{Name: "postgis", Run: func(ctx context.Context) (interface{}, error) {
+			var version string
+			if err := db.QueryRowContext(ctx, "SELECT PostGIS_Lib_Version()").Scan(&version); err != nil {
+				return nil, err
+			}
+			detail := map[string]string{"version": version}
+			if postGISMajor(version) < minPostGISMajor {
+				return detail, fmt.Errorf("PostGIS %s is installed, %d.0 or newer is required", version, minPostGISMajor)
+			}
+			return detail, nil
+		}},
+		{Name: "data", Run: func(ctx context.Context) (interface{}, error) {
+			return levelCounts(ctx, db, []levelMin{
+				{"propinsi", minRows.Propinsi},
+				{"kabupaten", minRows.Kabupaten},
+				{"kecamatan", minRows.Kecamatan},
+				{"kelurahan", minRows.Kelurahan},
+			})
+		}},
+		{Name: "migrations", Run: func(ctx context.Context) (interface{}, error) {
+			version, err := migrator.Version(ctx)
+			if err != nil {
+				return nil, err
+			}
+			detail := map[string]int{"version": version, "latest": migrator.Latest()}
+			if version < migrator.Latest() {
+				return detail, fmt.Errorf("schema is at migration %d, %d is required", version, migrator.Latest())
+			}
+			return detail, nil
+		}},
+	}, nil
+}
+
+// levelCounts mengambil jumlah baris setiap tabel level dari estimasi pg_class.reltuples, sehingga
+// probe yang sering tidak memindai tabel, dan gagal jika ada level dengan kurang dari minimumnya.
+// Estimasi yang di bawah minimum (mis. tabel belum di-ANALYZE setelah import) diganti hitungan
+// sebenarnya yang dibatasi sampai minimum tersebut.
+func levelCounts(ctx context.Context, db *sql.DB, levels []levelMin) (map[string]int, error) {
+	tables := make([]string, len(levels))
+	for i, l := range levels {
+		tables[i] = l.table
+	}
+
+	rows, err := db.QueryContext(ctx,
+		"SELECT relname, reltuples::bigint FROM pg_class WHERE oid = ANY($1::regclass[])", pq.Array(tables))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int, len(levels))
+	for rows.Next() {
+		var table string
+		var n int
+		if err := rows.Scan(&table, &n); err != nil {
+			return nil, err
+		}
+		counts[table] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, l := range levels {
+		if counts[l.table] >= l.min {
+			continue
+		}
+		var n int
+		query := "SELECT count(*) FROM (SELECT 1 FROM " + l.table + " LIMIT $1) t"
+		if err := db.QueryRowContext(ctx, query, l.min).Scan(&n); err != nil {
+			return nil, err
+		}
+		counts[l.table] = n
+		if n < l.min {
+			errs = append(errs, fmt.Errorf("%s has %d rows, at least %d expected", l.table, n, l.min))
+		}
+	}
+	return counts, errors.Join(errs...)
+}
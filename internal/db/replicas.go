@@ -0,0 +1,209 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"location-svc/internal/config"
+	"location-svc/internal/health"
+	"log/slog"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReplicaSet membagi query baca secara round-robin ke read replica yang sehat. Jika tidak ada
+// replica yang sehat (atau tidak ada replica sama sekali), query baca dijalankan di primary.
+// Replica yang gagal menjalankan query dikeluarkan dari rotasi lewat MarkUnhealthy.
+// Penulisan (migrate, import) tidak melewati ReplicaSet dan selalu memakai primary.
+type ReplicaSet struct {
+	primary  *sql.DB
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+type replica struct {
+	db      *sql.DB
+	name    string
+	healthy atomic.Bool
+}
+
+// OpenReplicas membuka pool koneksi ke setiap cfg.ReplicaURLs dengan pengaturan pool yang sama
+// seperti primary. Replica yang belum bisa dihubungi tidak menggagalkan startup: replica tersebut
+// tidak menerima query sampai Watch berhasil mem-ping-nya.
+func OpenReplicas(primary *sql.DB, cfg config.DBConfig) (*ReplicaSet, error) {
+	replicas := make([]*sql.DB, 0, len(cfg.ReplicaURLs))
+	for _, replicaURL := range cfg.ReplicaURLs {
+		db, err := sql.Open("postgres", withSessionParams(replicaURL))
+		if err != nil {
+			for _, opened := range replicas {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("open replica %s: %w", replicaName(replicaURL, len(replicas)), err)
+		}
+
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+		db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+		replicas = append(replicas, db)
+	}
+
+	set := newReplicaSet(primary, replicas, cfg.ReplicaURLs)
+	for _, r := range set.replicas {
+		if r.healthy.Load() {
+			slog.Info("Read replica connected", "replica", r.name)
+		} else {
+			slog.Warn("Read replica unreachable, reads go to other replicas or primary", "replica", r.name)
+		}
+	}
+	return set, nil
+}
+
+// NewReplicaSet creates new instance of ReplicaSet dan mem-ping setiap replica sekali
+// untuk menentukan status awalnya
+func NewReplicaSet(primary *sql.DB, replicas ...*sql.DB) *ReplicaSet {
+	return newReplicaSet(primary, replicas, nil)
+}
+
+// newReplicaSet membuat ReplicaSet; urls hanya dipakai untuk nama replica di log
+func newReplicaSet(primary *sql.DB, replicas []*sql.DB, urls []string) *ReplicaSet {
+	set := &ReplicaSet{primary: primary, replicas: make([]*replica, len(replicas))}
+
+	var wg sync.WaitGroup
+	for i, db := range replicas {
+		replicaURL := ""
+		if i < len(urls) {
+			replicaURL = urls[i]
+		}
+		r := &replica{db: db, name: replicaName(replicaURL, i)}
+		set.replicas[i] = r
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.healthy.Store(ping(context.Background(), r.db) == nil)
+		}()
+	}
+	wg.Wait()
+
+	return set
+}
+
+// Primary mengembalikan pool koneksi primary
+func (s *ReplicaSet) Primary() *sql.DB {
+	return s.primary
+}
+
+// Read mengembalikan pool koneksi untuk query baca berikutnya: replica sehat berikutnya
+// secara round-robin, atau primary jika tidak ada replica yang sehat
+func (s *ReplicaSet) Read() *sql.DB {
+	n := uint64(len(s.replicas))
+	start := s.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		if r := s.replicas[(start+i)%n]; r.healthy.Load() {
+			return r.db
+		}
+	}
+	return s.primary
+}
+
+// MarkUnhealthy mengeluarkan replica dengan pool db dari rotasi setelah query di replica tersebut
+// gagal, tanpa menunggu ping berikutnya. Replica dimasukkan kembali oleh Watch setelah ping
+// berhasil; db yang bukan replica (mis. primary) diabaikan.
+func (s *ReplicaSet) MarkUnhealthy(db *sql.DB, err error) {
+	for _, r := range s.replicas {
+		if r.db == db && r.healthy.Swap(false) {
+			slog.Error("Read replica query failed, removed from rotation", "replica", r.name, "error", err)
+		}
+	}
+}
+
+// Replicas mengembalikan pool koneksi semua replica, sehat maupun tidak, mis. untuk metrics
+func (s *ReplicaSet) Replicas() []*sql.DB {
+	dbs := make([]*sql.DB, len(s.replicas))
+	for i, r := range s.replicas {
+		dbs[i] = r.db
+	}
+	return dbs
+}
+
+// Healthy mengembalikan jumlah replica yang sehat dan jumlah seluruh replica
+func (s *ReplicaSet) Healthy() (healthy, total int) {
+	for _, r := range s.replicas {
+		if r.healthy.Load() {
+			healthy++
+		}
+	}
+	return healthy, len(s.replicas)
+}
+
+// Watch mem-ping setiap replica setiap interval sampai ctx selesai. Replica yang gagal di-ping
+// dikeluarkan dari rotasi dan dimasukkan kembali setelah ping berhasil; interval 0 menonaktifkan.
+func (s *ReplicaSet) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, r := range s.replicas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.watch(ctx, interval)
+		}()
+	}
+	wg.Wait()
+}
+
+func (r *replica) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := ping(ctx, r.db)
+		if ctx.Err() != nil {
+			return
+		}
+		switch healthy := err == nil; {
+		case !healthy && r.healthy.Swap(false):
+			slog.Error("Read replica unhealthy, removed from rotation", "replica", r.name, "error", err)
+		case healthy && !r.healthy.Swap(true):
+			slog.Info("Read replica healthy, added to rotation", "replica", r.name)
+		}
+	}
+}
+
+// Close menutup pool koneksi semua replica; primary ditutup terpisah oleh pemiliknya
+func (s *ReplicaSet) Close() error {
+	var errs []error
+	for _, r := range s.replicas {
+		errs = append(errs, r.db.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Check mengembalikan pemeriksaan readiness yang melaporkan jumlah replica sehat. Pemeriksaan ini
+// tidak pernah gagal karena query baca kembali ke primary saat semua replica tidak sehat.
+func (s *ReplicaSet) Check() health.Check {
+	return health.Check{Name: "replicas", Run: func(ctx context.Context) (interface{}, error) {
+		healthy, total := s.Healthy()
+		return map[string]int{"healthy": healthy, "total": total}, nil
+	}}
+}
+
+// replicaName mengembalikan host replica untuk log tanpa user dan password dari URL
+func replicaName(replicaURL string, i int) string {
+	if u, err := url.Parse(replicaURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return fmt.Sprintf("replica-%d", i+1)
+}
@@ -0,0 +1,146 @@
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"location-svc/internal/db"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeDriver menerima koneksi ke DSN apa pun; ping gagal selama DSN ada di down
+type fakeDriver struct {
+	mu   sync.Mutex
+	down map[string]bool
+}
+
+var replicaDriver = &fakeDriver{down: map[string]bool{}}
+
+func init() {
+	sql.Register("fake-replica", replicaDriver)
+}
+
+func (d *fakeDriver) setDown(dsn string, down bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.down[dsn] = down
+}
+
+func (d *fakeDriver) Open(dsn string) (driver.Conn, error) {
+	return &fakeConn{driver: d, dsn: dsn}, nil
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+	dsn    string
+}
+
+func (c *fakeConn) Ping(ctx context.Context) error {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	if c.driver.down[c.dsn] {
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func openFake(t *testing.T, dsn string) *sql.DB {
+	t.Helper()
+	database, err := sql.Open("fake-replica", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func TestReplicaSetRoundRobinSkipsUnhealthy(t *testing.T) {
+	replicaDriver.setDown("rr-down", true)
+	primary := openFake(t, "rr-primary")
+	up1, up2, down := openFake(t, "rr-up1"), openFake(t, "rr-up2"), openFake(t, "rr-down")
+
+	set := db.NewReplicaSet(primary, up1, down, up2)
+	if healthy, total := set.Healthy(); healthy != 2 || total != 3 {
+		t.Fatalf("Healthy() = %d/%d, want 2/3", healthy, total)
+	}
+
+	counts := map[*sql.DB]int{}
+	for range 10 {
+		counts[set.Read()]++
+	}
+	if counts[up1] == 0 || counts[up2] == 0 || counts[down] != 0 || counts[primary] != 0 {
+		t.Errorf("reads up1=%d up2=%d down=%d primary=%d, want only healthy replicas",
+			counts[up1], counts[up2], counts[down], counts[primary])
+	}
+}
+
+func TestReplicaSetFallsBackToPrimary(t *testing.T) {
+	primary := openFake(t, "fb-primary")
+	if got := db.NewReplicaSet(primary).Read(); got != primary {
+		t.Error("Read() without replicas should return primary")
+	}
+
+	replicaDriver.setDown("fb-replica", true)
+	if got := db.NewReplicaSet(primary, openFake(t, "fb-replica")).Read(); got != primary {
+		t.Error("Read() with only unhealthy replicas should return primary")
+	}
+}
+
+func TestReplicaSetWatchUpdatesRotation(t *testing.T) {
+	primary, replica := openFake(t, "watch-primary"), openFake(t, "watch-replica")
+	set := db.NewReplicaSet(primary, replica)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go set.Watch(ctx, 10*time.Millisecond)
+
+	waitFor := func(want *sql.DB) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for set.Read() != want {
+			if time.Now().After(deadline) {
+				t.Fatal("Watch did not update the replica rotation")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	replicaDriver.setDown("watch-replica", true)
+	waitFor(primary)
+	replicaDriver.setDown("watch-replica", false)
+	waitFor(replica)
+}
+
+func TestReplicaSetMarkUnhealthy(t *testing.T) {
+	primary, replica := openFake(t, "mark-primary"), openFake(t, "mark-replica")
+	set := db.NewReplicaSet(primary, replica)
+
+	set.MarkUnhealthy(primary, driver.ErrBadConn)
+	if got := set.Read(); got != replica {
+		t.Fatal("MarkUnhealthy(primary) should not change the replica rotation")
+	}
+
+	set.MarkUnhealthy(replica, driver.ErrBadConn)
+	if got := set.Read(); got != primary {
+		t.Fatal("Read() after MarkUnhealthy should skip the replica")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go set.Watch(ctx, 10*time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for set.Read() != replica {
+		if time.Now().After(deadline) {
+			t.Fatal("Watch did not return the replica to rotation after a successful ping")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,183 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"location-svc/internal/migrate"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// minPostGISMajor adalah versi mayor PostGIS minimum (ST_TileEnvelope untuk vector tiles butuh 3.0)
+const minPostGISMajor = 3
+
+// expectedTables berisi tabel dan kolom yang dipakai query di package repositories
+var expectedTables = []struct {
+	name    string
+	columns []string
+}{
+	{"propinsi", []string{"kd_propinsi", "nm_propinsi", "geom"}},
+	{"kabupaten", []string{"kd_kabupaten", "nm_kabupaten", "kd_propinsi", "geom"}},
+	{"kecamatan", []string{"kd_kecamatan", "nm_kecamatan", "kd_kabupaten", "geom"}},
+	{"kelurahan", []string{"kd_kelurahan", "nm_kelurahan", "kd_kecamatan", "geom"}},
+	{"dataset_version", []string{"version", "updated_at"}},
+}
+
+// expectedIndexes berisi index spatial, hierarki dan trigram yang dibuat oleh migrasi
+var expectedIndexes = []string{
+	"idx_propinsi_geom", "idx_kabupaten_geom", "idx_kecamatan_geom", "idx_kelurahan_geom",
+	"idx_kabupaten_kd_propinsi", "idx_kecamatan_kd_kabupaten", "idx_kelurahan_kd_kecamatan",
+	"idx_propinsi_nm_trgm", "idx_kabupaten_nm_trgm", "idx_kecamatan_nm_trgm", "idx_kelurahan_nm_trgm",
+}
+
+// SchemaError berisi semua masalah schema yang ditemukan VerifySchema
+type SchemaError struct {
+	Problems []string
+}
+
+func (e *SchemaError) Error() string {
+	return "database schema check failed:\n  - " + strings.Join(e.Problems, "\n  - ") +
+		"\nrun `location-svc migrate up` to create or upgrade the schema"
+}
+
+// VerifySchema memastikan extension, tabel, kolom, index dan migrasi yang dibutuhkan sudah ada.
+// Semua masalah dilaporkan sekaligus sebagai *SchemaError.
+func VerifySchema(db *sql.DB) error {
+	var problems []string
+
+	extensions, err := installedExtensions(db)
+	if err != nil {
+		return err
+	}
+	switch version, ok := extensions["postgis"]; {
+	case !ok:
+		problems = append(problems, "PostGIS extension is not installed")
+	case postGISMajor(version) < minPostGISMajor:
+		problems = append(problems, fmt.Sprintf("PostGIS %s is installed, %d.0 or newer is required", version, minPostGISMajor))
+	}
+	if _, ok := extensions["pg_trgm"]; !ok {
+		problems = append(problems, "pg_trgm extension is not installed")
+	}
+
+	columns, err := existingColumns(db)
+	if err != nil {
+		return err
+	}
+	for _, table := range expectedTables {
+		if columns[table.name] == nil {
+			problems = append(problems, "missing table "+table.name)
+			continue
+		}
+		for _, column := range table.columns {
+			if !columns[table.name][column] {
+				problems = append(problems, "missing column "+table.name+"."+column)
+			}
+		}
+	}
+
+	indexes, err := existingIndexes(db)
+	if err != nil {
+		return err
+	}
+	for _, index := range expectedIndexes {
+		if !indexes[index] {
+			problems = append(problems, "missing index "+index)
+		}
+	}
+
+	migrator, err := migrate.New(db)
+	if err != nil {
+		return err
+	}
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			problems = append(problems, fmt.Sprintf("migration %04d_%s is not applied", s.Version, s.Name))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &SchemaError{Problems: problems}
+	}
+	return nil
+}
+
+// installedExtensions mengembalikan extension yang terpasang beserta versinya
+func installedExtensions(db *sql.DB) (map[string]string, error) {
+	rows, err := db.Query("SELECT extname, extversion FROM pg_extension")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	extensions := make(map[string]string)
+	for rows.Next() {
+		var name, version string
+		if err := rows.Scan(&name, &version); err != nil {
+			return nil, err
+		}
+		extensions[name] = version
+	}
+	return extensions, rows.Err()
+}
+
+// existingColumns mengembalikan kolom per tabel yang diharapkan di schema aktif
+func existingColumns(db *sql.DB) (map[string]map[string]bool, error) {
+	tables := make([]string, len(expectedTables))
+	for i, t := range expectedTables {
+		tables[i] = t.name
+	}
+
+	rows, err := db.Query(`SELECT table_name, column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ANY($1)`, pq.Array(tables))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]map[string]bool)
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return nil, err
+		}
+		if columns[table] == nil {
+			columns[table] = make(map[string]bool)
+		}
+		columns[table][column] = true
+	}
+	return columns, rows.Err()
+}
+
+// existingIndexes mengembalikan nama index di schema aktif
+func existingIndexes(db *sql.DB) (map[string]bool, error) {
+	rows, err := db.Query("SELECT indexname FROM pg_indexes WHERE schemaname = current_schema()")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	indexes := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		indexes[name] = true
+	}
+	return indexes, rows.Err()
+}
+
+// postGISMajor membaca versi mayor dari extversion seperti "3.4.2"
+func postGISMajor(version string) int {
+	major, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+	if err != nil {
+		return 0
+	}
+	return major
+}
@@ -0,0 +1,147 @@
+// Package export menulis wilayah satu level secara streaming sebagai GeoJSON, GeoJSONSeq atau CSV
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"location-svc/internal/models"
+	"location-svc/internal/repositories"
+)
+
+// Format export yang didukung
+const (
+	FormatGeoJSON    = "geojson"
+	FormatGeoJSONSeq = "geojsonseq"
+	FormatCSV        = "csv"
+)
+
+// bufferSize adalah ukuran buffer tulis; data dikirim ke w setiap buffer penuh
+const bufferSize = 64 << 10
+
+// Writer menulis ExportRow satu per satu; Close menulis penutup dan mem-flush buffer
+type Writer interface {
+	Write(row models.ExportRow) error
+	Close() error
+}
+
+// format berisi metadata satu format export
+type format struct {
+	contentType string
+	extension   string
+	encoding    repositories.GeometryEncoding
+}
+
+var formats = map[string]format{
+	FormatGeoJSON:    {contentType: "application/geo+json", extension: "geojson", encoding: repositories.GeometryGeoJSON},
+	FormatGeoJSONSeq: {contentType: "application/geo+json-seq", extension: "geojsonl", encoding: repositories.GeometryGeoJSON},
+	FormatCSV:        {contentType: "text/csv; charset=utf-8", extension: "csv", encoding: repositories.GeometryWKT},
+}
+
+// IsFormat mengecek apakah format didukung
+func IsFormat(f string) bool {
+	_, ok := formats[f]
+	return ok
+}
+
+// ContentType mengembalikan media type format
+func ContentType(f string) string { return formats[f].contentType }
+
+// Extension mengembalikan ekstensi file format (tanpa titik)
+func Extension(f string) string { return formats[f].extension }
+
+// Encoding mengembalikan encoding geometri yang dibutuhkan format dari repository
+func Encoding(f string) repositories.GeometryEncoding { return formats[f].encoding }
+
+// NewWriter membuat Writer untuk format dan level tertentu di atas w
+func NewWriter(w io.Writer, f, level string) (Writer, error) {
+	buf := bufio.NewWriterSize(w, bufferSize)
+
+	switch f {
+	case FormatGeoJSON:
+		_, err := buf.WriteString(`{"type":"FeatureCollection","features":[`)
+		return &geoJSONWriter{buf: buf}, err
+	case FormatGeoJSONSeq:
+		return &geoJSONWriter{buf: buf, seq: true}, nil
+	case FormatCSV:
+		cw := &csvWriter{buf: buf, csv: csv.NewWriter(buf), columns: csvColumns(level)}
+		return cw, cw.csv.Write(append(cw.columns, "wkt"))
+	}
+	return nil, fmt.Errorf("unknown export format %q", f)
+}
+
+// geoJSONWriter menulis FeatureCollection, atau satu Feature per baris jika seq
+type geoJSONWriter struct {
+	buf   *bufio.Writer
+	seq   bool
+	count int
+	// scratch dipakai ulang untuk encode setiap Feature
+	scratch []byte
+}
+
+func (w *geoJSONWriter) Write(row models.ExportRow) error {
+	f := models.GeoJSONFeature{Type: "Feature", Properties: row.Properties, Geometry: json.RawMessage(row.Geometry)}
+	b, err := f.AppendJSON(w.scratch[:0])
+	if err != nil {
+		return err
+	}
+
+	if !w.seq && w.count > 0 {
+		w.buf.WriteByte(',')
+	}
+	w.count++
+
+	if w.seq {
+		b = append(b, '\n')
+	}
+	w.scratch = b
+	// bufio.Writer menyimpan error tulis sebelumnya, sehingga client yang terputus menghentikan export
+	_, err = w.buf.Write(b)
+	return err
+}
+
+func (w *geoJSONWriter) Close() error {
+	if !w.seq {
+		w.buf.WriteString("]}\n")
+	}
+	return w.buf.Flush()
+}
+
+// csvWriter menulis kolom kode/nama hierarki dan geometri WKT
+type csvWriter struct {
+	buf     *bufio.Writer
+	csv     *csv.Writer
+	columns []string
+}
+
+// csvColumns mengembalikan kolom kd_*/nm_* dari propinsi sampai level itu sendiri
+func csvColumns(level string) []string {
+	var columns []string
+	for _, l := range []string{"propinsi", "kabupaten", "kecamatan", "kelurahan"} {
+		columns = append(columns, "kd_"+l, "nm_"+l)
+		if l == level {
+			break
+		}
+	}
+	return columns
+}
+
+func (w *csvWriter) Write(row models.ExportRow) error {
+	p := row.Properties
+	hierarchy := []string{p.KdPropinsi, p.NmPropinsi, p.KdKabupaten, p.NmKabupaten, p.KdKecamatan, p.NmKecamatan}
+
+	record := make([]string, 0, len(w.columns)+1)
+	record = append(record, hierarchy[:len(w.columns)-2]...)
+	record = append(record, row.Code, p.Name, row.Geometry)
+	return w.csv.Write(record)
+}
+
+func (w *csvWriter) Close() error {
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return err
+	}
+	return w.buf.Flush()
+}
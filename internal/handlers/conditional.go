@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"location-svc/internal/cache"
+	"location-svc/internal/logging"
+	"location-svc/internal/models"
+	"location-svc/internal/repositories"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// datasetVersionTTL adalah lama versi dataset disimpan sebelum dibaca ulang dari database,
+// sehingga perubahan data terlihat di ETag paling lambat setelah interval ini
+const datasetVersionTTL = 5 * time.Second
+
+// Header validator yang tidak didefinisikan oleh echo
+const (
+	headerETag        = "ETag"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+// ConditionalGet menambahkan ETag dan Last-Modified berbasis versi dataset ke response GET,
+// dan menjawab 304 Not Modified untuk If-None-Match/If-Modified-Since yang masih cocok.
+// Precondition baru dievaluasi saat handler mengirim status 2xx, sehingga ID yang tidak ada
+// atau parameter yang tidak valid tetap dijawab 404/400; body yang ditulis handler setelah itu
+// dibuang dan response streaming (mis. export) dihentikan lewat error tulis.
+type ConditionalGet struct {
+	store    repositories.LocationStore
+	versions *cache.Cache[*models.DatasetVersion]
+}
+
+// NewConditionalGet membuat ConditionalGet yang membaca versi dataset dari store
+func NewConditionalGet(store repositories.LocationStore) *ConditionalGet {
+	return &ConditionalGet{
+		store:    store,
+		versions: cache.New[*models.DatasetVersion](cache.Options{MaxEntries: 1, TTL: datasetVersionTTL}),
+	}
+}
+
+// Middleware mengembalikan middleware untuk satu grup endpoint. cacheControl dipakai sebagai
+// header Cache-Control; string kosong berarti header tidak dikirim.
+func (g *ConditionalGet) Middleware(cacheControl string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if req.Method != http.MethodGet && req.Method != http.MethodHead {
+				return next(c)
+			}
+
+			// Tanpa versi dataset request tetap dilayani, hanya tanpa header validator
+			// Versi dipakai bersama semua request sehingga tidak ikut dibatalkan bersama request ini
+			version, err := g.versions.Get("", func() (*models.DatasetVersion, error) {
+				return g.store.DatasetVersion(context.WithoutCancel(req.Context()))
+			})
+			if err != nil {
+				logging.FromEcho(c).Warn("dataset version unavailable", "error", err)
+				return next(c)
+			}
+
+			etag := datasetETag(version.Version, req.URL)
+			lastModified := version.UpdatedAt.UTC().Truncate(time.Second)
+
+			res := c.Response()
+			header := res.Header()
+			header.Set(headerETag, etag)
+			header.Set(echo.HeaderLastModified, lastModified.Format(http.TimeFormat))
+			if cacheControl != "" {
+				header.Set(echo.HeaderCacheControl, cacheControl)
+			}
+
+			// Response error tidak boleh di-cache atau divalidasi ulang. Response 2xx yang
+			// precondition-nya cocok diganti 304 sebelum header dikirim.
+			w := &notModifiedWriter{ResponseWriter: res.Writer}
+			res.Writer = w
+			defer func() { res.Writer = w.ResponseWriter }()
+			res.Before(func() {
+				switch {
+				case res.Status >= http.StatusBadRequest:
+					header.Del(headerETag)
+					header.Del(echo.HeaderLastModified)
+					header.Del(echo.HeaderCacheControl)
+				case res.Status >= http.StatusOK && res.Status < http.StatusMultipleChoices && notModified(req, etag, lastModified):
+					res.Status = http.StatusNotModified
+					w.discard = true
+					for _, h := range []string{echo.HeaderContentType, echo.HeaderContentLength, echo.HeaderContentEncoding, echo.HeaderContentDisposition} {
+						header.Del(h)
+					}
+				}
+			})
+
+			if err := next(c); err != nil && !(w.discard && errors.Is(err, errNotModified)) {
+				return err
+			}
+			return nil
+		}
+	}
+}
+
+// errNotModified dikembalikan notModifiedWriter ke handler yang menulis body setelah
+// response diganti 304, agar handler streaming berhenti membaca dari database
+var errNotModified = errors.New("response replaced by 304 Not Modified")
+
+// notModifiedWriter menolak body setelah response diganti 304 Not Modified
+type notModifiedWriter struct {
+	http.ResponseWriter
+	discard bool
+}
+
+func (w *notModifiedWriter) Write(b []byte) (int, error) {
+	if w.discard {
+		return 0, errNotModified
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush diteruskan untuk response streaming seperti export
+func (w *notModifiedWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok && !w.discard {
+		f.Flush()
+	}
+}
+
+func (w *notModifiedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// datasetETag membuat weak ETag dari versi dataset, path dan query (urutan parameter diabaikan).
+// Weak karena representasi yang sama bisa dikirim dengan Content-Encoding berbeda.
+func datasetETag(version int64, u *url.URL) string {
+	h := fnv.New64a()
+	h.Write([]byte(u.Path))
+	h.Write([]byte{'?'})
+	h.Write([]byte(u.Query().Encode()))
+	return fmt.Sprintf(`W/"%d-%x"`, version, h.Sum64())
+}
+
+// notModified mengevaluasi precondition sesuai RFC 9110: If-None-Match (weak comparison)
+// diutamakan, If-Modified-Since hanya dipakai jika If-None-Match tidak ada
+func notModified(req *http.Request, etag string, lastModified time.Time) bool {
+	if inm := req.Header.Get(headerIfNoneMatch); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := req.Header.Get(echo.HeaderIfModifiedSince); ims != "" {
+		t, err := http.ParseTime(ims)
+		return err == nil && !lastModified.After(t)
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"location-svc/internal/apperror"
+	"location-svc/internal/logging"
+	"location-svc/internal/models"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HTTPErrorHandler adalah Echo HTTPErrorHandler terpusat yang memetakan *apperror.Error
+// dan *echo.HTTPError ke HTTP status serta body models.ErrorResponse yang konsisten.
+// Jika response sudah terkirim (mis. export yang gagal di tengah stream), error hanya dicatat.
+func HTTPErrorHandler(err error, c echo.Context) {
+	logger := logging.FromEcho(c)
+	if c.Response().Committed {
+		logger.Error("request failed after response was sent", "error", err)
+		return
+	}
+
+	status := http.StatusInternalServerError
+	body := models.ErrorResponse{
+		Code:      string(apperror.CodeInternal),
+		Message:   "Internal server error",
+		RequestID: c.Response().Header().Get(echo.HeaderXRequestID),
+	}
+
+	var httpErr *echo.HTTPError
+	if appErr, ok := apperror.As(err); ok {
+		status = appErr.HTTPStatus()
+		body.Code = string(appErr.Code)
+		body.Message = appErr.Message
+	} else if errors.As(err, &httpErr) {
+		status = httpErr.Code
+		body.Code = codeForStatus(status)
+		body.Message = http.StatusText(status)
+		if msg, ok := httpErr.Message.(string); ok {
+			body.Message = msg
+		}
+	}
+
+	if status >= http.StatusInternalServerError {
+		logger.Error("request failed", "status", status, "error", err)
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(status)
+	} else {
+		err = c.JSON(status, body)
+	}
+	if err != nil {
+		logger.Error("writing error response failed", "error", err)
+	}
+}
+
+// codeForStatus memetakan HTTP status dari *echo.HTTPError ke kode error
+func codeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return string(apperror.CodeInvalidArgument)
+	case http.StatusNotFound:
+		return string(apperror.CodeNotFound)
+	case http.StatusRequestEntityTooLarge:
+		return string(apperror.CodePayloadTooLarge)
+	case http.StatusServiceUnavailable:
+		return string(apperror.CodeUnavailable)
+	case http.StatusGatewayTimeout:
+		return string(apperror.CodeTimeout)
+	case apperror.StatusClientClosedRequest:
+		return string(apperror.CodeCanceled)
+	case http.StatusInternalServerError:
+		return string(apperror.CodeInternal)
+	default:
+		// mis. 405 menjadi METHOD_NOT_ALLOWED
+		return strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+	}
+}
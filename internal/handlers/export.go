@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"location-svc/internal/apperror"
+	"location-svc/internal/export"
+	"location-svc/internal/models"
+	"mime"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ExportLevel godoc
+// @Summary Export a whole level
+// @Description Stream every region of a level as a file, ordered by code. Rows are streamed from the database, so whole levels (e.g. all kelurahan) can be downloaded. GeoJSON properties match the /geojson FeatureCollection endpoints; CSV has kd_*/nm_* columns for the region and its parents plus a wkt column.
+// @Tags export
+// @Produce application/geo+json,application/geo+json-seq,text/csv
+// @Param level path string true "Level: propinsi, kabupaten, kecamatan or kelurahan"
+// @Param format query string false "geojson (default), geojsonseq (one Feature per line) or csv (WKT geometry)"
+// @Param parent query string false "Only export children of this parent code, digits only, e.g. 3201 (not for propinsi)"
+// @Param simplify query number false "Simplification tolerance in degrees (0-1), see /geojson endpoints"
+// @Param zoom query integer false "Map zoom level (0-22), see /geojson endpoints"
+// @Success 200 {file} binary
+// @Failure 400 {object} models.ErrorResponse
+// @Router /export/{level} [get]
+func (h *LocationHandler) ExportLevel(c echo.Context) error {
+	level := c.Param("level")
+	parent := c.QueryParam("parent")
+
+	format := c.QueryParam("format")
+	if format == "" {
+		format = export.FormatGeoJSON
+	}
+	if !export.IsFormat(format) {
+		return apperror.InvalidArgument("format must be geojson, geojsonseq or csv")
+	}
+
+	if parent != "" && !isRegionCode(parent) {
+		return apperror.InvalidArgument("parent must be a region code (digits only)")
+	}
+
+	tolerance, err := parseSimplifyTolerance(c)
+	if err != nil {
+		return err
+	}
+
+	filename := level
+	if parent != "" {
+		filename += "-" + parent
+	}
+
+	// Header baru dikirim saat baris pertama tersedia, sehingga error sebelum itu
+	// (level tidak valid, database tidak tersedia) tetap dikembalikan sebagai JSON
+	var w export.Writer
+	start := func() error {
+		if w != nil {
+			return nil
+		}
+
+		res := c.Response()
+		res.Header().Set(echo.HeaderContentType, export.ContentType(format))
+		res.Header().Set(echo.HeaderContentDisposition,
+			mime.FormatMediaType("attachment", map[string]string{"filename": filename + "." + export.Extension(format)}))
+		res.WriteHeader(http.StatusOK)
+
+		var err error
+		w, err = export.NewWriter(res, format, level)
+		return err
+	}
+
+	err = h.repo.ExportLevel(c.Request().Context(), level, parent, tolerance, export.Encoding(format), func(row models.ExportRow) error {
+		if err := start(); err != nil {
+			return err
+		}
+		return w.Write(row)
+	})
+	if err != nil {
+		// Jika response sudah terkirim sebagian, HTTPErrorHandler hanya mencatat error
+		return err
+	}
+
+	if err := start(); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// isRegionCode melaporkan apakah s berupa kode wilayah, yaitu hanya angka (mis. 3201). Kode
+// dengan titik seperti 32.01 ditolak karena kode wilayah di database disimpan tanpa pemisah.
+func isRegionCode(s string) bool {
+	if s == "" || len(s) > 32 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"location-svc/internal/compress"
+	"location-svc/internal/metrics"
+	"location-svc/internal/models"
+	"location-svc/internal/repositories"
+	"net/http"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+)
+
+// writeBufferSize adalah ukuran buffer saat menulis FeatureCollection ke response
+const writeBufferSize = 32 << 10
+
+// writeFeature menulis satu Feature sebagai response JSON
+func writeFeature(c echo.Context, f *models.GeoJSONFeature) error {
+	b, err := f.AppendJSON(make([]byte, 0, len(f.Geometry)+512))
+	if err != nil {
+		return err
+	}
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, b)
+}
+
+// featureCache diimplementasikan repositories.CachedStore
+type featureCache interface {
+	CachedGeoJSON(ctx context.Context, level, id string, tolerance float64) (*repositories.CachedFeature, error)
+}
+
+// serveFeature menulis GeoJSON satu wilayah. Jika store memakai cache, body yang sudah dikompresi
+// sesuai Accept-Encoding disimpan di entry cache dan dikirim apa adanya; middleware kompresi
+// melewati response yang sudah membawa Content-Encoding.
+func (h *LocationHandler) serveFeature(c echo.Context, level, id string, tolerance float64,
+	load func(ctx context.Context, id string, tolerance float64) (*models.GeoJSONFeature, error)) error {
+	ctx := c.Request().Context()
+	cached, ok := h.repo.(featureCache)
+	if !ok {
+		feature, err := load(ctx, id, tolerance)
+		if err != nil {
+			return err
+		}
+		return writeFeature(c, feature)
+	}
+
+	entry, err := cached.CachedGeoJSON(ctx, level, id, tolerance)
+	if err != nil {
+		return err
+	}
+
+	encoding := compress.Negotiate(c.Request().Header.Get(echo.HeaderAcceptEncoding))
+	body, err := entry.Body(encoding, func(b []byte) ([]byte, error) {
+		return compress.Encode(encoding, b)
+	})
+	if err != nil {
+		return err
+	}
+	metrics.SetPayloadSize(c, entry.JSONLength())
+
+	header := c.Response().Header()
+	if !slices.Contains(header.Values(echo.HeaderVary), echo.HeaderAcceptEncoding) {
+		header.Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+	}
+	if encoding != compress.Identity {
+		header.Set(echo.HeaderContentEncoding, encoding)
+	}
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, body)
+}
+
+// featureCollectionWriter menulis FeatureCollection ke response feature demi feature selagi
+// baris dibaca dari database, tanpa membangun seluruh body di memori. Header dan pembuka
+// collection baru ditulis saat feature pertama tersedia atau saat Close, sehingga error sebelum
+// itu (mis. parent tidak ditemukan) tetap dikembalikan sebagai JSON dengan status yang sesuai.
+type featureCollectionWriter struct {
+	c     echo.Context
+	w     *bufio.Writer
+	buf   []byte
+	count int
+}
+
+func (fw *featureCollectionWriter) start() {
+	if fw.w != nil {
+		return
+	}
+
+	res := fw.c.Response()
+	res.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	res.WriteHeader(http.StatusOK)
+
+	fw.w = bufio.NewWriterSize(res, writeBufferSize)
+	fw.w.WriteString(`{"type":"FeatureCollection","features":[`)
+}
+
+// Write menulis satu feature. Error tulis (client terputus) disimpan bufio.Writer dan
+// dikembalikan di sini, sehingga query di repository ikut dihentikan.
+func (fw *featureCollectionWriter) Write(f *models.GeoJSONFeature) error {
+	fw.start()
+	if fw.count > 0 {
+		fw.w.WriteByte(',')
+	}
+	fw.count++
+
+	var err error
+	if fw.buf, err = f.AppendJSON(fw.buf[:0]); err != nil {
+		return err
+	}
+	_, err = fw.w.Write(fw.buf)
+	return err
+}
+
+// Close menulis penutup collection (atau collection kosong) dan mem-flush buffer
+func (fw *featureCollectionWriter) Close() error {
+	fw.start()
+	fw.w.WriteString("]}")
+	return fw.w.Flush()
+}
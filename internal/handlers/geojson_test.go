@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"location-svc/internal/models"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// largeGeometry membuat MultiPolygon dengan n vertex berformat seperti output ST_AsGeoJSON
+func largeGeometry(n int) []byte {
+	var b strings.Builder
+	b.WriteString(`{"type":"MultiPolygon","coordinates":[[[`)
+	for i := 0; i <= n; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		a := 2 * math.Pi * float64(i%n) / float64(n)
+		fmt.Fprintf(&b, "[%.9f,%.9f]", 106.8+0.5*math.Cos(a), -6.2+0.5*math.Sin(a))
+	}
+	b.WriteString(`]]]}`)
+	return []byte(b.String())
+}
+
+func largeFeature() *models.GeoJSONFeature {
+	return &models.GeoJSONFeature{
+		Type:       "Feature",
+		Properties: models.GeoJSONProperties{ID: 32, Name: "Jawa Barat", Type: "propinsi"},
+		Geometry:   largeGeometry(50000),
+	}
+}
+
+func TestFeatureCollectionWriter(t *testing.T) {
+	for _, features := range [][]models.GeoJSONFeature{{*largeFeature(), *largeFeature()}, {}} {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		w := &featureCollectionWriter{c: e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)}
+		for i := range features {
+			if err := w.Write(&features[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		want, _ := json.Marshal(models.GeoJSONFeatureCollection{Type: "FeatureCollection", Features: features})
+		if !bytes.Equal(rec.Body.Bytes(), want) {
+			t.Errorf("body differs from json.Marshal (len %d vs %d)", rec.Body.Len(), len(want))
+		}
+	}
+}
+
+// discardResponse adalah http.ResponseWriter yang membuang body, agar benchmark collection
+// mengukur memori writer dan bukan body yang terkumpul di recorder
+type discardResponse struct{ header http.Header }
+
+func (d *discardResponse) Header() http.Header         { return d.header }
+func (d *discardResponse) Write(b []byte) (int, error) { return len(b), nil }
+func (d *discardResponse) WriteHeader(int)             {}
+
+// BenchmarkFeature membandingkan encoding Feature 50.000 vertex:
+//   - decode: alur lama, geometry di-decode ke map[string]interface{} lalu di-encode ulang oleh c.JSON
+//   - rawmessage: geometry json.RawMessage lewat json.Marshal (masih divalidasi dan di-compact ulang)
+//   - passthrough: GeoJSONFeature.AppendJSON yang dipakai handler
+//   - collection: 20 feature ditulis featureCollectionWriter ke response satu per satu,
+//     memori per operasi tetap sebesar satu feature walaupun collection membesar
+func BenchmarkFeature(b *testing.B) {
+	f := largeFeature()
+	b.SetBytes(int64(len(f.Geometry)))
+
+	b.Run("decode", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var geometry map[string]interface{}
+			if err := json.Unmarshal(f.Geometry, &geometry); err != nil {
+				b.Fatal(err)
+			}
+			legacy := struct {
+				Type       string                   `json:"type"`
+				Properties models.GeoJSONProperties `json:"properties"`
+				Geometry   map[string]interface{}   `json:"geometry"`
+			}{f.Type, f.Properties, geometry}
+			if _, err := json.Marshal(legacy); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("rawmessage", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := json.Marshal(f); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("passthrough", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := f.AppendJSON(make([]byte, 0, len(f.Geometry)+512)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("collection", func(b *testing.B) {
+		const n = 20
+		b.SetBytes(int64(n * len(f.Geometry)))
+		b.ReportAllocs()
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		for i := 0; i < b.N; i++ {
+			w := &featureCollectionWriter{c: e.NewContext(req, &discardResponse{header: http.Header{}})}
+			for j := 0; j < n; j++ {
+				if err := w.Write(f); err != nil {
+					b.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"location-svc/internal/health"
+	"location-svc/internal/models"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type HealthHandler struct {
+	state *health.State
+}
+
+// NewHealthHandler creates new instance of HealthHandler
+func NewHealthHandler(state *health.State) *HealthHandler {
+	return &HealthHandler{state: state}
+}
+
+// Livez godoc
+// @Summary Liveness probe
+// @Description Reports that the process is running and serving HTTP. Does not touch the database, so a database outage does not restart the service.
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.HealthReport
+// @Router /livez [get]
+func (h *HealthHandler) Livez(c echo.Context) error {
+	return c.JSON(http.StatusOK, models.HealthReport{Status: health.StatusOK})
+}
+
+// Readyz godoc
+// @Summary Readiness probe
+// @Description Checks database connectivity, PostGIS, the row count of every level and the applied migration version. Returns 503 with the failing checks, or SHUTTING_DOWN during graceful shutdown. Every check reports its own latency.
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.HealthReport
+// @Failure 503 {object} models.HealthReport
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c echo.Context) error {
+	report := h.state.Ready(c.Request().Context())
+
+	status := http.StatusOK
+	if report.Status != health.StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	// Hasil readiness selalu dihitung ulang
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.JSON(status, report)
+}
@@ -1,19 +1,27 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
+	"location-svc/internal/apperror"
+	"location-svc/internal/logging"
 	"location-svc/internal/models"
 	"location-svc/internal/repositories"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 )
 
 type LocationHandler struct {
-	repo *repositories.LocationRepository
+	repo repositories.LocationStore
 }
 
 // NewLocationHandler creates new instance of LocationHandler
-func NewLocationHandler(repo *repositories.LocationRepository) *LocationHandler {
+func NewLocationHandler(repo repositories.LocationStore) *LocationHandler {
 	return &LocationHandler{repo: repo}
 }
 
@@ -23,28 +31,34 @@ func NewLocationHandler(repo *repositories.LocationRepository) *LocationHandler
 // @Tags search
 // @Accept json
 // @Produce json
-// @Param name query string false "Search by province name"
-// @Success 200 {array} models.Propinsi
+// @Param name query string false "Search by province name (typo tolerant, ranked by relevance)"
+// @Param limit query integer false "Page size (default 50, max 1000)"
+// @Param offset query integer false "Number of rows to skip, cannot be combined with cursor"
+// @Param cursor query string false "Keyset cursor from next_cursor of the previous page"
+// @Success 200 {object} models.Page[models.Propinsi]
+// @Failure 400 {object} models.ErrorResponse
 // @Router /search/propinsi [get]
 func (h *LocationHandler) GetPropinsi(c echo.Context) error {
 	name := c.QueryParam("name")
 
-	var provinces []models.Propinsi
-	var err error
+	page, err := parsePageParams(c)
+	if err != nil {
+		return err
+	}
+
+	var provinces *models.Page[models.Propinsi]
 
 	if name != "" {
-		provinces, err = h.repo.SearchPropinsiByName(name)
+		provinces, err = h.repo.SearchPropinsiByName(c.Request().Context(), name, page)
 	} else {
-		provinces, err = h.repo.GetPropinsi()
+		provinces, err = h.repo.GetPropinsi(c.Request().Context(), page)
 	}
 
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get provinces",
-		})
+		return err
 	}
 
-	return c.JSON(http.StatusOK, provinces)
+	return respondPage(c, provinces)
 }
 
 // GetKabupaten godoc
@@ -54,39 +68,43 @@ func (h *LocationHandler) GetPropinsi(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param propinsi_id query string true "Province ID"
-// @Param name query string false "Search by regency name"
-// @Success 200 {array} models.Kabupaten
+// @Param name query string false "Search by regency name (typo tolerant, ranked by relevance)"
+// @Param limit query integer false "Page size (default 50, max 1000)"
+// @Param offset query integer false "Number of rows to skip, cannot be combined with cursor"
+// @Param cursor query string false "Keyset cursor from next_cursor of the previous page"
+// @Success 200 {object} models.Page[models.Kabupaten]
+// @Failure 400 {object} models.ErrorResponse
 // @Router /search/kabupaten [get]
 func (h *LocationHandler) GetKabupaten(c echo.Context) error {
 	propinsiIDStr := c.QueryParam("propinsi_id")
 	name := c.QueryParam("name")
 
 	if propinsiIDStr == "" && name == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "propinsi_id is required when not searching by name",
-		})
+		return apperror.InvalidArgument("propinsi_id is required when not searching by name")
+	}
+
+	page, err := parsePageParams(c)
+	if err != nil {
+		return err
 	}
 
-	var kabupatens []models.Kabupaten
-	var err error
+	var kabupatens *models.Page[models.Kabupaten]
 
 	if name != "" {
 		var propinsiID *string
 		if propinsiIDStr != "" {
 			propinsiID = &propinsiIDStr
 		}
-		kabupatens, err = h.repo.SearchKabupatenByName(name, propinsiID)
+		kabupatens, err = h.repo.SearchKabupatenByName(c.Request().Context(), name, propinsiID, page)
 	} else {
-		kabupatens, err = h.repo.GetKabupaten(propinsiIDStr)
+		kabupatens, err = h.repo.GetKabupaten(c.Request().Context(), propinsiIDStr, page)
 	}
 
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get regencies",
-		})
+		return err
 	}
 
-	return c.JSON(http.StatusOK, kabupatens)
+	return respondPage(c, kabupatens)
 }
 
 // GetKecamatan godoc
@@ -97,8 +115,12 @@ func (h *LocationHandler) GetKabupaten(c echo.Context) error {
 // @Produce json
 // @Param propinsi_id query string true "Province ID"
 // @Param kabupaten_id query string true "Regency ID"
-// @Param name query string false "Search by district name"
-// @Success 200 {array} models.Kecamatan
+// @Param name query string false "Search by district name (typo tolerant, ranked by relevance)"
+// @Param limit query integer false "Page size (default 50, max 1000)"
+// @Param offset query integer false "Number of rows to skip, cannot be combined with cursor"
+// @Param cursor query string false "Keyset cursor from next_cursor of the previous page"
+// @Success 200 {object} models.Page[models.Kecamatan]
+// @Failure 400 {object} models.ErrorResponse
 // @Router /search/kecamatan [get]
 func (h *LocationHandler) GetKecamatan(c echo.Context) error {
 	propinsiIDStr := c.QueryParam("propinsi_id")
@@ -106,13 +128,15 @@ func (h *LocationHandler) GetKecamatan(c echo.Context) error {
 	name := c.QueryParam("name")
 
 	if name == "" && (propinsiIDStr == "" || kabupatenIDStr == "") {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "propinsi_id and kabupaten_id are required when not searching by name",
-		})
+		return apperror.InvalidArgument("propinsi_id and kabupaten_id are required when not searching by name")
 	}
 
-	var kecamatans []models.Kecamatan
-	var err error
+	page, err := parsePageParams(c)
+	if err != nil {
+		return err
+	}
+
+	var kecamatans *models.Page[models.Kecamatan]
 
 	if name != "" {
 		var propinsiID, kabupatenID *string
@@ -125,18 +149,16 @@ func (h *LocationHandler) GetKecamatan(c echo.Context) error {
 			kabupatenID = &kabupatenIDStr
 		}
 
-		kecamatans, err = h.repo.SearchKecamatanByName(name, propinsiID, kabupatenID)
+		kecamatans, err = h.repo.SearchKecamatanByName(c.Request().Context(), name, propinsiID, kabupatenID, page)
 	} else {
-		kecamatans, err = h.repo.GetKecamatan(propinsiIDStr, kabupatenIDStr)
+		kecamatans, err = h.repo.GetKecamatan(c.Request().Context(), propinsiIDStr, kabupatenIDStr, page)
 	}
 
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get districts",
-		})
+		return err
 	}
 
-	return c.JSON(http.StatusOK, kecamatans)
+	return respondPage(c, kecamatans)
 }
 
 // GetKelurahan godoc
@@ -148,8 +170,12 @@ func (h *LocationHandler) GetKecamatan(c echo.Context) error {
 // @Param propinsi_id query string true "Province ID"
 // @Param kabupaten_id query string true "Regency ID"
 // @Param kecamatan_id query string true "District ID"
-// @Param name query string false "Search by village name"
-// @Success 200 {array} models.Kelurahan
+// @Param name query string false "Search by village name (typo tolerant, ranked by relevance)"
+// @Param limit query integer false "Page size (default 50, max 1000)"
+// @Param offset query integer false "Number of rows to skip, cannot be combined with cursor"
+// @Param cursor query string false "Keyset cursor from next_cursor of the previous page"
+// @Success 200 {object} models.Page[models.Kelurahan]
+// @Failure 400 {object} models.ErrorResponse
 // @Router /search/kelurahan [get]
 func (h *LocationHandler) GetKelurahan(c echo.Context) error {
 	propinsiIDStr := c.QueryParam("propinsi_id")
@@ -158,13 +184,15 @@ func (h *LocationHandler) GetKelurahan(c echo.Context) error {
 	name := c.QueryParam("name")
 
 	if name == "" && (propinsiIDStr == "" || kabupatenIDStr == "" || kecamatanIDStr == "") {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "propinsi_id, kabupaten_id, and kecamatan_id are required when not searching by name",
-		})
+		return apperror.InvalidArgument("propinsi_id, kabupaten_id, and kecamatan_id are required when not searching by name")
+	}
+
+	page, err := parsePageParams(c)
+	if err != nil {
+		return err
 	}
 
-	var kelurahans []models.Kelurahan
-	var err error
+	var kelurahans *models.Page[models.Kelurahan]
 
 	if name != "" {
 		var propinsiID, kabupatenID, kecamatanID *string
@@ -181,18 +209,57 @@ func (h *LocationHandler) GetKelurahan(c echo.Context) error {
 			kecamatanID = &kecamatanIDStr
 		}
 
-		kelurahans, err = h.repo.SearchKelurahanByName(name, propinsiID, kabupatenID, kecamatanID)
+		kelurahans, err = h.repo.SearchKelurahanByName(c.Request().Context(), name, propinsiID, kabupatenID, kecamatanID, page)
 	} else {
-		kelurahans, err = h.repo.GetKelurahan(propinsiIDStr, kabupatenIDStr, kecamatanIDStr)
+		kelurahans, err = h.repo.GetKelurahan(c.Request().Context(), propinsiIDStr, kabupatenIDStr, kecamatanIDStr, page)
+	}
+
+	if err != nil {
+		return err
 	}
 
+	return respondPage(c, kelurahans)
+}
+
+const (
+	// defaultSearchLimit adalah jumlah hasil autocomplete jika limit tidak diisi
+	defaultSearchLimit = 10
+	// maxSearchLimit adalah batas atas hasil autocomplete; nilai yang lebih besar dipotong
+	maxSearchLimit = 50
+)
+
+// Search godoc
+// @Summary Search all administrative levels
+// @Description Autocomplete search across provinces, regencies, districts and villages at once, ranked by relevance with a full breadcrumb
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param q query string true "Search text (typo tolerant)"
+// @Param limit query integer false "Maximum number of results (default 10, max 50)"
+// @Success 200 {array} models.SearchResult
+// @Failure 400 {object} models.ErrorResponse
+// @Router /search [get]
+func (h *LocationHandler) Search(c echo.Context) error {
+	q := strings.TrimSpace(c.QueryParam("q"))
+	if q == "" {
+		return apperror.InvalidArgument("q is required")
+	}
+
+	limit := defaultSearchLimit
+	if s := c.QueryParam("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return apperror.InvalidArgument("limit must be a positive integer")
+		}
+		limit = min(n, maxSearchLimit)
+	}
+
+	results, err := h.repo.Search(c.Request().Context(), q, limit)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get villages",
-		})
+		return err
 	}
 
-	return c.JSON(http.StatusOK, kelurahans)
+	return c.JSON(http.StatusOK, results)
 }
 
 // GetPropinsiGeoJSON godoc
@@ -202,19 +269,21 @@ func (h *LocationHandler) GetKelurahan(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param id path string true "Province ID"
+// @Param simplify query number false "Simplification tolerance in degrees (0-1). Rounded down to the nearest zoom tolerance (360 / (256 * 2^z), z 0-22; smaller values keep full resolution) and vertices are snapped to a grid of that size, so shared borders stay identical between adjacent regions across all endpoints. Larger values shrink the payload but coarsen borders: 0.0001 ≈ 11 m, 0.001 ≈ 110 m, 0.01 ≈ 1.1 km"
+// @Param zoom query integer false "Map zoom level (0-22). Tolerance is derived as one pixel of a 256px tile at this zoom, so the geometry is as detailed as the map can display. Ignored when simplify is set"
 // @Success 200 {object} models.GeoJSONFeature
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Router /geojson/propinsi/{id} [get]
 func (h *LocationHandler) GetPropinsiGeoJSON(c echo.Context) error {
 	id := c.Param("id")
 
-	geojson, err := h.repo.GetPropinsiGeoJSON(id)
+	tolerance, err := parseSimplifyTolerance(c)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get province GeoJSON",
-		})
+		return err
 	}
 
-	return c.JSON(http.StatusOK, geojson)
+	return h.serveFeature(c, "propinsi", id, tolerance, h.repo.GetPropinsiGeoJSON)
 }
 
 // GetKabupatenGeoJSON godoc
@@ -224,19 +293,21 @@ func (h *LocationHandler) GetPropinsiGeoJSON(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param id path string true "Regency ID"
+// @Param simplify query number false "Simplification tolerance in degrees (0-1). Rounded down to the nearest zoom tolerance (360 / (256 * 2^z), z 0-22; smaller values keep full resolution) and vertices are snapped to a grid of that size, so shared borders stay identical between adjacent regions across all endpoints. Larger values shrink the payload but coarsen borders: 0.0001 ≈ 11 m, 0.001 ≈ 110 m, 0.01 ≈ 1.1 km"
+// @Param zoom query integer false "Map zoom level (0-22). Tolerance is derived as one pixel of a 256px tile at this zoom, so the geometry is as detailed as the map can display. Ignored when simplify is set"
 // @Success 200 {object} models.GeoJSONFeature
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Router /geojson/kabupaten/{id} [get]
 func (h *LocationHandler) GetKabupatenGeoJSON(c echo.Context) error {
 	id := c.Param("id")
 
-	geojson, err := h.repo.GetKabupatenGeoJSON(id)
+	tolerance, err := parseSimplifyTolerance(c)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get regency GeoJSON",
-		})
+		return err
 	}
 
-	return c.JSON(http.StatusOK, geojson)
+	return h.serveFeature(c, "kabupaten", id, tolerance, h.repo.GetKabupatenGeoJSON)
 }
 
 // GetKecamatanGeoJSON godoc
@@ -246,19 +317,21 @@ func (h *LocationHandler) GetKabupatenGeoJSON(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param id path string true "District ID"
+// @Param simplify query number false "Simplification tolerance in degrees (0-1). Rounded down to the nearest zoom tolerance (360 / (256 * 2^z), z 0-22; smaller values keep full resolution) and vertices are snapped to a grid of that size, so shared borders stay identical between adjacent regions across all endpoints. Larger values shrink the payload but coarsen borders: 0.0001 ≈ 11 m, 0.001 ≈ 110 m, 0.01 ≈ 1.1 km"
+// @Param zoom query integer false "Map zoom level (0-22). Tolerance is derived as one pixel of a 256px tile at this zoom, so the geometry is as detailed as the map can display. Ignored when simplify is set"
 // @Success 200 {object} models.GeoJSONFeature
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Router /geojson/kecamatan/{id} [get]
 func (h *LocationHandler) GetKecamatanGeoJSON(c echo.Context) error {
 	id := c.Param("id")
 
-	geojson, err := h.repo.GetKecamatanGeoJSON(id)
+	tolerance, err := parseSimplifyTolerance(c)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get district GeoJSON",
-		})
+		return err
 	}
 
-	return c.JSON(http.StatusOK, geojson)
+	return h.serveFeature(c, "kecamatan", id, tolerance, h.repo.GetKecamatanGeoJSON)
 }
 
 // GetKelurahanGeoJSON godoc
@@ -268,17 +341,312 @@ func (h *LocationHandler) GetKecamatanGeoJSON(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param id path string true "Village ID"
+// @Param simplify query number false "Simplification tolerance in degrees (0-1). Rounded down to the nearest zoom tolerance (360 / (256 * 2^z), z 0-22; smaller values keep full resolution) and vertices are snapped to a grid of that size, so shared borders stay identical between adjacent regions across all endpoints. Larger values shrink the payload but coarsen borders: 0.0001 ≈ 11 m, 0.001 ≈ 110 m, 0.01 ≈ 1.1 km"
+// @Param zoom query integer false "Map zoom level (0-22). Tolerance is derived as one pixel of a 256px tile at this zoom, so the geometry is as detailed as the map can display. Ignored when simplify is set"
 // @Success 200 {object} models.GeoJSONFeature
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Router /geojson/kelurahan/{id} [get]
 func (h *LocationHandler) GetKelurahanGeoJSON(c echo.Context) error {
 	id := c.Param("id")
 
-	geojson, err := h.repo.GetKelurahanGeoJSON(id)
+	tolerance, err := parseSimplifyTolerance(c)
+	if err != nil {
+		return err
+	}
+
+	return h.serveFeature(c, "kelurahan", id, tolerance, h.repo.GetKelurahanGeoJSON)
+}
+
+// GetPropinsiKabupatenGeoJSON godoc
+// @Summary Get regencies GeoJSON of a province
+// @Description Get a FeatureCollection of all regencies in a specific province, with parent codes/names in properties
+// @Tags geojson
+// @Accept json
+// @Produce json
+// @Param id path string true "Province ID"
+// @Param simplify query number false "Simplification tolerance in degrees (0-1). Rounded down to the nearest zoom tolerance (360 / (256 * 2^z), z 0-22; smaller values keep full resolution) and vertices are snapped to a grid of that size, so shared borders stay identical between adjacent regions across all endpoints. Larger values shrink the payload but coarsen borders: 0.0001 ≈ 11 m, 0.001 ≈ 110 m, 0.01 ≈ 1.1 km"
+// @Param zoom query integer false "Map zoom level (0-22). Tolerance is derived as one pixel of a 256px tile at this zoom, so the geometry is as detailed as the map can display. Ignored when simplify is set"
+// @Success 200 {object} models.GeoJSONFeatureCollection
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /geojson/propinsi/{id}/kabupaten [get]
+func (h *LocationHandler) GetPropinsiKabupatenGeoJSON(c echo.Context) error {
+	id := c.Param("id")
+
+	tolerance, err := parseSimplifyTolerance(c)
+	if err != nil {
+		return err
+	}
+
+	w := &featureCollectionWriter{c: c}
+	if err := h.repo.GetKabupatenFeatureCollection(c.Request().Context(), id, tolerance, w.Write); err != nil {
+		// Jika response sudah terkirim sebagian, HTTPErrorHandler hanya mencatat error
+		return err
+	}
+	return w.Close()
+}
+
+// GetKabupatenKecamatanGeoJSON godoc
+// @Summary Get districts GeoJSON of a regency
+// @Description Get a FeatureCollection of all districts in a specific regency, with parent codes/names in properties
+// @Tags geojson
+// @Accept json
+// @Produce json
+// @Param id path string true "Regency ID"
+// @Param simplify query number false "Simplification tolerance in degrees (0-1). Rounded down to the nearest zoom tolerance (360 / (256 * 2^z), z 0-22; smaller values keep full resolution) and vertices are snapped to a grid of that size, so shared borders stay identical between adjacent regions across all endpoints. Larger values shrink the payload but coarsen borders: 0.0001 ≈ 11 m, 0.001 ≈ 110 m, 0.01 ≈ 1.1 km"
+// @Param zoom query integer false "Map zoom level (0-22). Tolerance is derived as one pixel of a 256px tile at this zoom, so the geometry is as detailed as the map can display. Ignored when simplify is set"
+// @Success 200 {object} models.GeoJSONFeatureCollection
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /geojson/kabupaten/{id}/kecamatan [get]
+func (h *LocationHandler) GetKabupatenKecamatanGeoJSON(c echo.Context) error {
+	id := c.Param("id")
+
+	tolerance, err := parseSimplifyTolerance(c)
+	if err != nil {
+		return err
+	}
+
+	w := &featureCollectionWriter{c: c}
+	if err := h.repo.GetKecamatanFeatureCollection(c.Request().Context(), id, tolerance, w.Write); err != nil {
+		// Jika response sudah terkirim sebagian, HTTPErrorHandler hanya mencatat error
+		return err
+	}
+	return w.Close()
+}
+
+// GetKecamatanKelurahanGeoJSON godoc
+// @Summary Get villages GeoJSON of a district
+// @Description Get a FeatureCollection of all villages in a specific district, with parent codes/names in properties
+// @Tags geojson
+// @Accept json
+// @Produce json
+// @Param id path string true "District ID"
+// @Param simplify query number false "Simplification tolerance in degrees (0-1). Rounded down to the nearest zoom tolerance (360 / (256 * 2^z), z 0-22; smaller values keep full resolution) and vertices are snapped to a grid of that size, so shared borders stay identical between adjacent regions across all endpoints. Larger values shrink the payload but coarsen borders: 0.0001 ≈ 11 m, 0.001 ≈ 110 m, 0.01 ≈ 1.1 km"
+// @Param zoom query integer false "Map zoom level (0-22). Tolerance is derived as one pixel of a 256px tile at this zoom, so the geometry is as detailed as the map can display. Ignored when simplify is set"
+// @Success 200 {object} models.GeoJSONFeatureCollection
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /geojson/kecamatan/{id}/kelurahan [get]
+func (h *LocationHandler) GetKecamatanKelurahanGeoJSON(c echo.Context) error {
+	id := c.Param("id")
+
+	tolerance, err := parseSimplifyTolerance(c)
+	if err != nil {
+		return err
+	}
+
+	w := &featureCollectionWriter{c: c}
+	if err := h.repo.GetKelurahanFeatureCollection(c.Request().Context(), id, tolerance, w.Write); err != nil {
+		// Jika response sudah terkirim sebagian, HTTPErrorHandler hanya mencatat error
+		return err
+	}
+	return w.Close()
+}
+
+// ReverseGeocode godoc
+// @Summary Reverse geocode a coordinate
+// @Description Get the full administrative hierarchy (propinsi to kelurahan) containing a point. Points on a shared border resolve to a single kelurahan deterministically.
+// @Tags reverse
+// @Accept json
+// @Produce json
+// @Param lat query number true "Latitude (WGS84)"
+// @Param lon query number true "Longitude (WGS84)"
+// @Success 200 {object} models.Kelurahan
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /reverse [get]
+func (h *LocationHandler) ReverseGeocode(c echo.Context) error {
+	lat, errLat := strconv.ParseFloat(c.QueryParam("lat"), 64)
+	lon, errLon := strconv.ParseFloat(c.QueryParam("lon"), 64)
+
+	if errLat != nil || errLon != nil {
+		return apperror.InvalidArgument("lat and lon are required and must be numbers")
+	}
+
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return apperror.InvalidArgument("lat must be between -90 and 90, lon between -180 and 180")
+	}
+
+	kelurahan, err := h.repo.ReverseGeocode(c.Request().Context(), lat, lon)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, kelurahan)
+}
+
+const (
+	// maxSimplifyTolerance adalah batas atas parameter simplify (derajat)
+	maxSimplifyTolerance = 1.0
+	// maxZoom adalah zoom level tertinggi yang didukung untuk penyederhanaan
+	maxZoom = repositories.MaxSimplifyZoom
+)
+
+// parseSimplifyTolerance membaca query param simplify atau zoom dan mengembalikan tolerance
+// dalam derajat. Tolerance 0 berarti geometry dikembalikan dalam resolusi penuh.
+func parseSimplifyTolerance(c echo.Context) (float64, error) {
+	if s := c.QueryParam("simplify"); s != "" {
+		tolerance, err := strconv.ParseFloat(s, 64)
+		if err != nil || tolerance < 0 || tolerance > maxSimplifyTolerance {
+			return 0, apperror.InvalidArgument("simplify must be a number between 0 and 1")
+		}
+		return repositories.QuantizeTolerance(tolerance), nil
+	}
+
+	if z := c.QueryParam("zoom"); z != "" {
+		zoom, err := strconv.Atoi(z)
+		if err != nil || zoom < 0 || zoom > maxZoom {
+			return 0, apperror.InvalidArgument("zoom must be an integer between 0 and 22")
+		}
+		return repositories.ZoomTolerance(zoom), nil
+	}
+
+	return 0, nil
+}
+
+const (
+	// maxBatchPoints adalah jumlah maksimum titik dalam satu request batch
+	maxBatchPoints = 50000
+	// batchChunkSize adalah jumlah titik yang di-resolve dalam satu query ke database
+	batchChunkSize = 1000
+	// maxBatchBodySize adalah ukuran maksimum body request batch, cukup untuk maxBatchPoints
+	// titik dengan ID panjang
+	maxBatchBodySize = 16 << 20
+)
+
+// BatchReverseGeocode godoc
+// @Summary Batch reverse geocode coordinates
+// @Description Resolve many points at once. Accepts a JSON array or NDJSON (Content-Type: application/x-ndjson) of {id, lat, lon}, at most 50000 points and 16 MB. Results are returned in input order with per-item errors: code is INVALID_ARGUMENT, NOT_FOUND, or the database error of the point's chunk (e.g. TIMEOUT, UNAVAILABLE). The request fails with that error instead if it is canceled or every chunk fails.
+// @Tags reverse
+// @Accept json
+// @Accept x-ndjson
+// @Produce json
+// @Param points body []models.ReversePoint true "Points to resolve"
+// @Success 200 {array} models.ReverseResult
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /reverse/batch [post]
+func (h *LocationHandler) BatchReverseGeocode(c echo.Context) error {
+	req := c.Request()
+	req.Body = http.MaxBytesReader(c.Response(), req.Body, maxBatchBodySize)
+
+	points, err := decodeReversePoints(req)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return apperror.New(apperror.CodePayloadTooLarge, "Request body too large, maximum is "+strconv.Itoa(maxBatchBodySize>>20)+" MB")
+	}
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get village GeoJSON",
-		})
+		return apperror.InvalidArgument("Invalid request body: " + err.Error())
+	}
+
+	if len(points) > maxBatchPoints {
+		return apperror.New(apperror.CodePayloadTooLarge, "Too many points, maximum is "+strconv.Itoa(maxBatchPoints))
+	}
+
+	results := make([]models.ReverseResult, len(points))
+
+	// Hanya titik yang valid yang dikirim ke database, indeks aslinya disimpan
+	var indexes []int
+	var lats, lons []float64
+	for i, p := range points {
+		results[i].ID = p.ID
+
+		switch {
+		case p.Lat == nil || p.Lon == nil:
+			setReverseError(&results[i], apperror.InvalidArgument("lat and lon are required"))
+		case *p.Lat < -90 || *p.Lat > 90 || *p.Lon < -180 || *p.Lon > 180:
+			setReverseError(&results[i], apperror.InvalidArgument("lat must be between -90 and 90, lon between -180 and 180"))
+		default:
+			indexes = append(indexes, i)
+			lats = append(lats, *p.Lat)
+			lons = append(lons, *p.Lon)
+		}
+	}
+
+	// Chunk yang gagal hanya menandai titik-titiknya, kecuali request dibatalkan atau semua chunk
+	// gagal: response 200 berisi error di setiap titik tidak berguna bagi client
+	ctx := c.Request().Context()
+	chunks, failed := 0, 0
+	var lastErr error
+	for start := 0; start < len(indexes); start += batchChunkSize {
+		end := min(start+batchChunkSize, len(indexes))
+		chunks++
+
+		kelurahans, err := h.repo.BatchReverseGeocode(ctx, lats[start:end], lons[start:end])
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			failed++
+			lastErr = err
+			logging.FromEcho(c).Error("batch reverse geocode chunk failed", "points", end-start, "error", err)
+		}
+
+		for j, idx := range indexes[start:end] {
+			switch {
+			case err != nil:
+				setReverseError(&results[idx], err)
+			case kelurahans[j] == nil:
+				setReverseError(&results[idx], apperror.NotFound("No region found at the given coordinate"))
+			default:
+				results[idx].Result = kelurahans[j]
+			}
+		}
+	}
+
+	if chunks > 0 && failed == chunks {
+		return lastErr
+	}
+	return c.JSON(http.StatusOK, results)
+}
+
+// setReverseError mengisi Code dan Error hasil batch dari err. Error yang bukan apperror
+// dilaporkan sebagai INTERNAL tanpa detailnya, seperti HTTPErrorHandler.
+func setReverseError(result *models.ReverseResult, err error) {
+	result.Code, result.Error = string(apperror.CodeInternal), "Internal server error"
+	if appErr, ok := apperror.As(err); ok {
+		result.Code, result.Error = string(appErr.Code), appErr.Message
+	}
+}
+
+// decodeReversePoints membaca body request sebagai JSON array atau NDJSON secara streaming.
+// Pembacaan berhenti setelah maxBatchPoints+1 titik sehingga batch yang terlalu besar
+// ditolak tanpa memuat seluruh body.
+func decodeReversePoints(req *http.Request) ([]models.ReversePoint, error) {
+	dec := json.NewDecoder(req.Body)
+
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
+	ndjson := mediaType == "application/x-ndjson" || mediaType == "application/jsonl"
+
+	if !ndjson {
+		if tok, err := dec.Token(); err != nil {
+			return nil, err
+		} else if tok != json.Delim('[') {
+			return nil, errors.New("expected a JSON array of points")
+		}
+	}
+
+	points := []models.ReversePoint{}
+	for len(points) <= maxBatchPoints {
+		if !ndjson && !dec.More() {
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		var p models.ReversePoint
+		if err := dec.Decode(&p); err == io.EOF && ndjson {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
 	}
 
-	return c.JSON(http.StatusOK, geojson)
+	return points, nil
 }
@@ -0,0 +1,926 @@
+package handlers_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"location-svc/internal/apperror"
+	"location-svc/internal/cache"
+	"location-svc/internal/compress"
+	"location-svc/internal/config"
+	"location-svc/internal/handlers"
+	"location-svc/internal/health"
+	"location-svc/internal/models"
+	"location-svc/internal/repositories"
+	"location-svc/internal/routes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func newTestServer() *echo.Echo {
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.HTTPErrorHandler
+	e.Use(middleware.RequestID())
+	routes.Setup(e, repositories.NewMemoryStore(repositories.SampleFixtures()), config.Default(), &health.State{})
+	return e
+}
+
+func doRequest(t *testing.T, e *echo.Echo, method, target, contentType, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set(echo.HeaderContentType, contentType)
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+
+	var v T
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+	}
+	return v
+}
+
+func assertError(t *testing.T, rec *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+
+	if rec.Code != status {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, status, rec.Body.String())
+	}
+
+	body := decode[models.ErrorResponse](t, rec)
+	if body.Code != code {
+		t.Errorf("code = %q, want %q", body.Code, code)
+	}
+	if body.Message == "" {
+		t.Error("message is empty")
+	}
+	if body.RequestID == "" || body.RequestID != rec.Header().Get(echo.HeaderXRequestID) {
+		t.Errorf("request_id = %q, want X-Request-Id header %q", body.RequestID, rec.Header().Get(echo.HeaderXRequestID))
+	}
+}
+
+func TestHealth(t *testing.T) {
+	e := echo.New()
+	state := &health.State{}
+	routes.Setup(e, repositories.NewMemoryStore(repositories.SampleFixtures()), config.Default(), state)
+
+	rec := doRequest(t, e, http.MethodGet, "/health", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+
+	// Saat shutdown readiness gagal agar load balancer berhenti mengirim request
+	state.StartShutdown()
+	rec = doRequest(t, e, http.MethodGet, "/health", "", "")
+	if rec.Code != http.StatusServiceUnavailable || decode[map[string]interface{}](t, rec)["status"] != "SHUTTING_DOWN" {
+		t.Fatalf("status = %d (body %s), want 503 SHUTTING_DOWN", rec.Code, rec.Body.String())
+	}
+}
+
+func TestLivezReadyz(t *testing.T) {
+	failing := errors.New("connection refused")
+	state := health.NewState(time.Second,
+		health.Check{Name: "database", Run: func(ctx context.Context) (interface{}, error) { return nil, nil }},
+		health.Check{Name: "data", Run: func(ctx context.Context) (interface{}, error) {
+			return map[string]int{"propinsi": 0}, failing
+		}},
+	)
+	e := echo.New()
+	routes.Setup(e, repositories.NewMemoryStore(repositories.SampleFixtures()), config.Default(), state)
+
+	rec := doRequest(t, e, http.MethodGet, "/livez", "", "")
+	if rec.Code != http.StatusOK || decode[models.HealthReport](t, rec).Status != "OK" {
+		t.Fatalf("livez = %d %s, want 200 OK", rec.Code, rec.Body.String())
+	}
+
+	rec = doRequest(t, e, http.MethodGet, "/readyz", "", "")
+	report := decode[models.HealthReport](t, rec)
+	if rec.Code != http.StatusServiceUnavailable || report.Status != "FAIL" {
+		t.Fatalf("readyz = %d %s, want 503 FAIL", rec.Code, rec.Body.String())
+	}
+	if got := report.Checks["database"]; got.Status != "OK" || got.Error != "" {
+		t.Errorf("database check = %+v, want OK", got)
+	}
+	if got := report.Checks["data"]; got.Status != "FAIL" || got.Error != failing.Error() || got.Detail == nil {
+		t.Errorf("data check = %+v, want FAIL with detail", got)
+	}
+
+	// Liveness tetap OK saat shutdown, readiness gagal tanpa menjalankan pemeriksaan
+	state.StartShutdown()
+	if rec := doRequest(t, e, http.MethodGet, "/livez", "", ""); rec.Code != http.StatusOK {
+		t.Errorf("livez during shutdown = %d, want 200", rec.Code)
+	}
+	rec = doRequest(t, e, http.MethodGet, "/readyz", "", "")
+	if report := decode[models.HealthReport](t, rec); rec.Code != http.StatusServiceUnavailable || report.Status != "SHUTTING_DOWN" || report.Checks != nil {
+		t.Errorf("readyz during shutdown = %d %s, want 503 SHUTTING_DOWN", rec.Code, rec.Body.String())
+	}
+}
+
+func TestUnknownRoute(t *testing.T) {
+	rec := doRequest(t, newTestServer(), http.MethodGet, "/does-not-exist", "", "")
+	assertError(t, rec, http.StatusNotFound, "NOT_FOUND")
+}
+
+func TestSearchPropinsi(t *testing.T) {
+	e := newTestServer()
+
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{"/search/propinsi", []string{"DKI Jakarta", "Jawa Barat", "Jawa Tengah"}},
+		{"/search/propinsi?name=jawa", []string{"Jawa Barat", "Jawa Tengah"}},
+		{"/search/propinsi?name=sumatera", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			rec := doRequest(t, e, http.MethodGet, tt.target, "", "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+
+			var got []string
+			for _, p := range decode[models.Page[models.Propinsi]](t, rec).Data {
+				got = append(got, p.NmPropinsi)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchKabupaten(t *testing.T) {
+	e := newTestServer()
+
+	rec := doRequest(t, e, http.MethodGet, "/search/kabupaten", "", "")
+	assertError(t, rec, http.StatusBadRequest, "INVALID_ARGUMENT")
+
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{"/search/kabupaten?propinsi_id=32", []string{"3201", "3202"}},
+		{"/search/kabupaten?name=jakarta", []string{"3171", "3172"}},
+		{"/search/kabupaten?name=bogor&propinsi_id=31", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			rec := doRequest(t, e, http.MethodGet, tt.target, "", "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+
+			var got []string
+			for _, k := range decode[models.Page[models.Kabupaten]](t, rec).Data {
+				got = append(got, k.KdKabupaten)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchKecamatan(t *testing.T) {
+	e := newTestServer()
+
+	rec := doRequest(t, e, http.MethodGet, "/search/kecamatan?propinsi_id=32", "", "")
+	assertError(t, rec, http.StatusBadRequest, "INVALID_ARGUMENT")
+
+	rec = doRequest(t, e, http.MethodGet, "/search/kecamatan?propinsi_id=32&kabupaten_id=3201", "", "")
+	got := decode[models.Page[models.Kecamatan]](t, rec).Data
+	if len(got) != 2 || got[0].NmKecamatan != "Leuwiliang" || got[1].NmKecamatan != "Nanggung" {
+		t.Errorf("got %+v, want Leuwiliang and Nanggung", got)
+	}
+
+	rec = doRequest(t, e, http.MethodGet, "/search/kecamatan?name=jaga", "", "")
+	got = decode[models.Page[models.Kecamatan]](t, rec).Data
+	if len(got) != 1 || got[0].NmKabupaten != "Kota Jakarta Selatan" || got[0].NmPropinsi != "DKI Jakarta" {
+		t.Errorf("got %+v, want Jagakarsa with full hierarchy", got)
+	}
+}
+
+func TestSearchKelurahan(t *testing.T) {
+	e := newTestServer()
+
+	rec := doRequest(t, e, http.MethodGet, "/search/kelurahan?propinsi_id=32&kabupaten_id=3201", "", "")
+	assertError(t, rec, http.StatusBadRequest, "INVALID_ARGUMENT")
+
+	rec = doRequest(t, e, http.MethodGet, "/search/kelurahan?propinsi_id=32&kabupaten_id=3201&kecamatan_id=3201010", "", "")
+	got := decode[models.Page[models.Kelurahan]](t, rec).Data
+	if len(got) != 2 {
+		t.Fatalf("got %d villages, want 2", len(got))
+	}
+
+	rec = doRequest(t, e, http.MethodGet, "/search/kelurahan?name=leuwi&kabupaten_id=3201", "", "")
+	got = decode[models.Page[models.Kelurahan]](t, rec).Data
+	if len(got) != 1 || got[0].KdKelurahan != "3201020001" || got[0].KdKecamatan != "3201020" {
+		t.Errorf("got %+v, want Leuwiliang 3201020001", got)
+	}
+}
+
+func TestSearchFuzzy(t *testing.T) {
+	e := newTestServer()
+
+	// Typo tolerated via trigram similarity
+	rec := doRequest(t, e, http.MethodGet, "/search/kabupaten?name=Sukabumy", "", "")
+	got := decode[models.Page[models.Kabupaten]](t, rec).Data
+	if len(got) != 1 || got[0].KdKabupaten != "3202" || got[0].Score <= 0 {
+		t.Errorf("got %+v, want Kabupaten Sukabumi with a score", got)
+	}
+
+	// Word-prefix match ranked before the fuzzy match, regardless of alphabetical order
+	rec = doRequest(t, e, http.MethodGet, "/search/kabupaten?name=jakarta+timur", "", "")
+	got = decode[models.Page[models.Kabupaten]](t, rec).Data
+	if len(got) != 2 || got[0].KdKabupaten != "3172" || got[1].KdKabupaten != "3171" {
+		t.Fatalf("got %+v, want 3172 then 3171", got)
+	}
+	if got[0].Score != 1 || got[1].Score >= got[0].Score {
+		t.Errorf("scores = %v, %v, want 1 then lower", got[0].Score, got[1].Score)
+	}
+
+	// Ranked results still paginate with the keyset cursor
+	rec = doRequest(t, e, http.MethodGet, "/search/kabupaten?name=jakarta+timur&limit=1", "", "")
+	page := decode[models.Page[models.Kabupaten]](t, rec)
+	if page.NextCursor == "" {
+		t.Fatal("next_cursor is empty")
+	}
+	rec = doRequest(t, e, http.MethodGet, "/search/kabupaten?name=jakarta+timur&limit=1&cursor="+page.NextCursor, "", "")
+	got = decode[models.Page[models.Kabupaten]](t, rec).Data
+	if len(got) != 1 || got[0].KdKabupaten != "3171" {
+		t.Errorf("second page = %+v, want 3171", got)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	e := newTestServer()
+
+	rec := doRequest(t, e, http.MethodGet, "/search", "", "")
+	assertError(t, rec, http.StatusBadRequest, "INVALID_ARGUMENT")
+
+	rec = doRequest(t, e, http.MethodGet, "/search?q=x&limit=0", "", "")
+	assertError(t, rec, http.StatusBadRequest, "INVALID_ARGUMENT")
+
+	// Same name on two levels: the higher level comes first
+	rec = doRequest(t, e, http.MethodGet, "/search?q=jagakarsa", "", "")
+	got := decode[[]models.SearchResult](t, rec)
+	want := []models.SearchResult{
+		{Level: "kecamatan", Code: "3171010", Name: "Jagakarsa", Breadcrumb: "Jagakarsa, Kota Jakarta Selatan, DKI Jakarta", Score: 1},
+		{Level: "kelurahan", Code: "3171010001", Name: "Jagakarsa", Breadcrumb: "Jagakarsa, Jagakarsa, Kota Jakarta Selatan, DKI Jakarta", Score: 1},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// Prefix matches across levels rank before substring matches
+	rec = doRequest(t, e, http.MethodGet, "/search?q=leuwi&limit=1", "", "")
+	got = decode[[]models.SearchResult](t, rec)
+	if len(got) != 1 || got[0].Level != "kecamatan" || got[0].Code != "3201020" {
+		t.Errorf("got %+v, want kecamatan Leuwiliang only", got)
+	}
+
+	// Fuzzy matches (Jagakarsa) come after every substring match
+	rec = doRequest(t, e, http.MethodGet, "/search?q=jakarta&limit=3", "", "")
+	var levels []string
+	for _, r := range decode[[]models.SearchResult](t, rec) {
+		levels = append(levels, r.Level+":"+r.Code)
+	}
+	if strings.Join(levels, ",") != "propinsi:31,kabupaten:3171,kabupaten:3172" {
+		t.Errorf("got %v, want DKI Jakarta then the two Jakarta regencies", levels)
+	}
+}
+
+func TestSearchPagination(t *testing.T) {
+	e := newTestServer()
+
+	// Walk all provinces one per page using the keyset cursor
+	var names []string
+	target := "/search/propinsi?limit=1"
+	for target != "" {
+		rec := doRequest(t, e, http.MethodGet, target, "", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want 200", target, rec.Code)
+		}
+
+		page := decode[models.Page[models.Propinsi]](t, rec)
+		if page.Total != 3 || page.Limit != 1 || len(page.Data) != 1 {
+			t.Fatalf("%s: got total=%d limit=%d len=%d", target, page.Total, page.Limit, len(page.Data))
+		}
+		names = append(names, page.Data[0].NmPropinsi)
+
+		target = ""
+		if page.NextCursor != "" {
+			link := rec.Header().Get("Link")
+			if !strings.Contains(link, "cursor="+page.NextCursor) || !strings.HasSuffix(link, `rel="next"`) {
+				t.Errorf("Link = %q, want next cursor %q", link, page.NextCursor)
+			}
+			target = "/search/propinsi?limit=1&cursor=" + page.NextCursor
+		}
+	}
+	if strings.Join(names, ",") != "DKI Jakarta,Jawa Barat,Jawa Tengah" {
+		t.Errorf("walked %v", names)
+	}
+
+	rec := doRequest(t, e, http.MethodGet, "/search/kabupaten?propinsi_id=32&offset=1", "", "")
+	page := decode[models.Page[models.Kabupaten]](t, rec)
+	if page.Total != 2 || page.Offset != 1 || len(page.Data) != 1 || page.Data[0].KdKabupaten != "3202" || page.NextCursor != "" {
+		t.Errorf("offset page = %+v", page)
+	}
+	if rec.Header().Get("Link") != "" {
+		t.Errorf("last page should not have a Link header, got %q", rec.Header().Get("Link"))
+	}
+
+	rec = doRequest(t, e, http.MethodGet, "/search/propinsi?limit=5000", "", "")
+	if page := decode[models.Page[models.Propinsi]](t, rec); page.Limit != 1000 {
+		t.Errorf("limit = %d, want clamped to 1000", page.Limit)
+	}
+
+	for _, target := range []string{
+		"/search/propinsi?limit=0",
+		"/search/propinsi?offset=-1",
+		"/search/propinsi?cursor=not-a-cursor",
+		"/search/propinsi?offset=1&cursor=WyJhIiwiYiJd",
+	} {
+		rec = doRequest(t, e, http.MethodGet, target, "", "")
+		assertError(t, rec, http.StatusBadRequest, "INVALID_ARGUMENT")
+	}
+}
+
+func TestGeoJSON(t *testing.T) {
+	e := newTestServer()
+
+	tests := []struct {
+		level string
+		id    string
+		name  string
+	}{
+		{"propinsi", "32", "Jawa Barat"},
+		{"kabupaten", "3201", "Kabupaten Bogor"},
+		{"kecamatan", "3171010", "Jagakarsa"},
+		{"kelurahan", "3201010002", "Kalibunder"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			rec := doRequest(t, e, http.MethodGet, "/geojson/"+tt.level+"/"+tt.id, "", "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+
+			feature := decode[models.GeoJSONFeature](t, rec)
+			if feature.Type != "Feature" || feature.Properties.Name != tt.name || feature.Properties.Type != tt.level {
+				t.Errorf("got %+v", feature)
+			}
+			var geometry struct{ Type string }
+			if err := json.Unmarshal(feature.Geometry, &geometry); err != nil || geometry.Type != "MultiPolygon" {
+				t.Errorf("geometry = %s, want MultiPolygon", feature.Geometry)
+			}
+
+			rec = doRequest(t, e, http.MethodGet, "/geojson/"+tt.level+"/999", "", "")
+			assertError(t, rec, http.StatusNotFound, "NOT_FOUND")
+
+			rec = doRequest(t, e, http.MethodGet, "/geojson/"+tt.level+"/"+tt.id+"?zoom=8", "", "")
+			if rec.Code != http.StatusOK {
+				t.Errorf("zoom=8: status = %d, want 200", rec.Code)
+			}
+
+			rec = doRequest(t, e, http.MethodGet, "/geojson/"+tt.level+"/"+tt.id+"?simplify=-1", "", "")
+			assertError(t, rec, http.StatusBadRequest, "INVALID_ARGUMENT")
+
+			rec = doRequest(t, e, http.MethodGet, "/geojson/"+tt.level+"/"+tt.id+"?zoom=99", "", "")
+			assertError(t, rec, http.StatusBadRequest, "INVALID_ARGUMENT")
+		})
+	}
+}
+
+func TestGeoJSONCache(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.HTTPErrorHandler
+	store := repositories.NewCachedStore(repositories.NewMemoryStore(repositories.SampleFixtures()), cache.Options{MaxEntries: 10})
+	routes.Setup(e, store, config.Default(), &health.State{})
+
+	for _, target := range []string{
+		"/geojson/propinsi/32", "/geojson/propinsi/32", "/geojson/propinsi/32?zoom=8",
+		// Dibulatkan ke tolerance zoom 8 sehingga memakai entry yang sama
+		"/geojson/propinsi/32?simplify=0.01", "/geojson/propinsi/32?simplify=0.0101",
+		"/geojson/propinsi/99", "/geojson/propinsi/99",
+	} {
+		doRequest(t, e, http.MethodGet, target, "", "")
+	}
+
+	// Not found tidak disimpan, sehingga kedua request ke 99 menjadi miss
+	rec := doRequest(t, e, http.MethodGet, "/health", "", "")
+	health := decode[struct {
+		GeoJSONCache cache.Stats `json:"geojson_cache"`
+	}](t, rec)
+	got := health.GeoJSONCache
+	if got.Bytes <= 0 {
+		t.Errorf("geojson_cache.bytes = %d, want the size of the cached features", got.Bytes)
+	}
+	got.Bytes = 0
+	if want := (cache.Stats{Hits: 3, Misses: 4, Entries: 2}); got != want {
+		t.Errorf("geojson_cache = %+v, want %+v", got, want)
+	}
+}
+
+func TestGeoJSONPrecompressed(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.HTTPErrorHandler
+	e.Use(compress.Middleware(0))
+	store := repositories.NewCachedStore(repositories.NewMemoryStore(repositories.SampleFixtures()), cache.Options{MaxEntries: 10})
+	routes.Setup(e, store, config.Default(), &health.State{})
+
+	get := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/geojson/propinsi/32", nil)
+		req.Header.Set(echo.HeaderAcceptEncoding, accept)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	plain := get("").Body.String()
+	if !strings.HasPrefix(plain, `{"type":"Feature"`) {
+		t.Fatalf("identity body = %q", plain)
+	}
+
+	// Body gzip yang sama dipakai ulang dan tidak dikompresi dua kali oleh middleware
+	first, second := get("gzip"), get("gzip")
+	if first.Header().Get(echo.HeaderContentEncoding) != "gzip" || !bytes.Equal(first.Body.Bytes(), second.Body.Bytes()) {
+		t.Fatalf("Content-Encoding = %q, bodies equal = %v", first.Header().Get(echo.HeaderContentEncoding), bytes.Equal(first.Body.Bytes(), second.Body.Bytes()))
+	}
+	zr, err := gzip.NewReader(first.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := io.ReadAll(zr); string(b) != plain {
+		t.Errorf("decompressed body = %q, want %q", b, plain)
+	}
+	if got := get("br").Header().Get(echo.HeaderContentEncoding); got != "br" {
+		t.Errorf("br Content-Encoding = %q", got)
+	}
+
+	if s := store.CacheStats(); s.Misses != 1 || s.Hits != 3 {
+		t.Errorf("cache stats = %+v, want one miss for all encodings", s)
+	}
+}
+
+func TestConditionalGet(t *testing.T) {
+	e := newTestServer()
+
+	rec := doRequest(t, e, http.MethodGet, "/geojson/propinsi/32?zoom=8&simplify=0", "", "")
+	etag, lastModified := rec.Header().Get("ETag"), rec.Header().Get(echo.HeaderLastModified)
+	if rec.Code != http.StatusOK || !strings.HasPrefix(etag, `W/"1-`) || lastModified == "" {
+		t.Fatalf("status = %d, ETag = %q, Last-Modified = %q", rec.Code, etag, lastModified)
+	}
+	if got := rec.Header().Get(echo.HeaderCacheControl); got != config.Default().Cache.Control.GeoJSON {
+		t.Errorf("Cache-Control = %q, want geojson policy", got)
+	}
+
+	conditional := func(target, header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set(header, value)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		header string
+		value  string
+		want   int
+	}{
+		{"matching etag, query reordered", "/geojson/propinsi/32?simplify=0&zoom=8", "If-None-Match", `"x", ` + etag, http.StatusNotModified},
+		{"strong form of weak etag", "/geojson/propinsi/32?zoom=8&simplify=0", "If-None-Match", strings.TrimPrefix(etag, "W/"), http.StatusNotModified},
+		{"other url", "/geojson/propinsi/31", "If-None-Match", etag, http.StatusOK},
+		{"not modified since", "/geojson/propinsi/32", echo.HeaderIfModifiedSince, lastModified, http.StatusNotModified},
+		{"modified since", "/geojson/propinsi/32", echo.HeaderIfModifiedSince, "Mon, 01 Jan 2001 00:00:00 GMT", http.StatusOK},
+		{"any etag", "/geojson/propinsi/32", "If-None-Match", "*", http.StatusNotModified},
+		// Precondition hanya berlaku untuk response sukses
+		{"unknown id, any etag", "/geojson/propinsi/does-not-exist", "If-None-Match", "*", http.StatusNotFound},
+		{"unknown id, not modified since", "/geojson/propinsi/does-not-exist", echo.HeaderIfModifiedSince, lastModified, http.StatusNotFound},
+		{"invalid params, any etag", "/geojson/propinsi/32?zoom=99", "If-None-Match", "*", http.StatusBadRequest},
+		{"unknown tile level, not modified since", "/tiles/desa/1/0/0", echo.HeaderIfModifiedSince, lastModified, http.StatusBadRequest},
+		{"streamed export", "/export/propinsi", echo.HeaderIfModifiedSince, lastModified, http.StatusNotModified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := conditional(tt.target, tt.header, tt.value)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusNotModified && (rec.Body.Len() != 0 || rec.Header().Get("ETag") == "" || rec.Header().Get(echo.HeaderContentType) != "") {
+				t.Errorf("304 body = %q, headers = %v", rec.Body.String(), rec.Header())
+			}
+		})
+	}
+
+	// Search memakai kebijakan Cache-Control sendiri; error tidak membawa header cache
+	rec = doRequest(t, e, http.MethodGet, "/search/propinsi", "", "")
+	if got := rec.Header().Get(echo.HeaderCacheControl); got != config.Default().Cache.Control.Search {
+		t.Errorf("search Cache-Control = %q", got)
+	}
+	rec = doRequest(t, e, http.MethodGet, "/geojson/propinsi/99", "", "")
+	if rec.Code != http.StatusNotFound || rec.Header().Get("ETag") != "" || rec.Header().Get(echo.HeaderCacheControl) != "" {
+		t.Errorf("404 status = %d, headers = %v", rec.Code, rec.Header())
+	}
+}
+
+func TestGeoJSONChildren(t *testing.T) {
+	e := newTestServer()
+
+	tests := []struct {
+		target string
+		want   int
+		check  func(p models.GeoJSONProperties) bool
+	}{
+		{"/geojson/propinsi/31/kabupaten", 2, func(p models.GeoJSONProperties) bool {
+			return p.Type == "kabupaten" && p.NmPropinsi == "DKI Jakarta"
+		}},
+		{"/geojson/kabupaten/3201/kecamatan", 2, func(p models.GeoJSONProperties) bool {
+			return p.Type == "kecamatan" && p.KdPropinsi == "32" && p.NmKabupaten == "Kabupaten Bogor"
+		}},
+		{"/geojson/kecamatan/3201010/kelurahan", 2, func(p models.GeoJSONProperties) bool {
+			return p.Type == "kelurahan" && p.KdKabupaten == "3201" && p.NmKecamatan == "Nanggung"
+		}},
+		{"/geojson/kecamatan/3202010/kelurahan", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			rec := doRequest(t, e, http.MethodGet, tt.target, "", "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+
+			collection := decode[models.GeoJSONFeatureCollection](t, rec)
+			if collection.Type != "FeatureCollection" || len(collection.Features) != tt.want {
+				t.Fatalf("got %s with %d features, want FeatureCollection with %d", collection.Type, len(collection.Features), tt.want)
+			}
+			if tt.want == 0 && !strings.Contains(rec.Body.String(), `"features":[]`) {
+				t.Errorf("empty collection should encode features as [], got %s", rec.Body.String())
+			}
+			for _, f := range collection.Features {
+				if !tt.check(f.Properties) {
+					t.Errorf("unexpected properties %+v", f.Properties)
+				}
+			}
+		})
+	}
+}
+
+// toleranceStore mencatat tolerance yang diterima store dari setiap endpoint
+type toleranceStore struct {
+	*repositories.MemoryStore
+	got []float64
+}
+
+func (s *toleranceStore) GetKabupatenGeoJSON(ctx context.Context, id string, tolerance float64) (*models.GeoJSONFeature, error) {
+	s.got = append(s.got, tolerance)
+	return s.MemoryStore.GetKabupatenGeoJSON(ctx, id, tolerance)
+}
+
+func (s *toleranceStore) GetKabupatenFeatureCollection(ctx context.Context, propinsiID string, tolerance float64, fn func(*models.GeoJSONFeature) error) error {
+	s.got = append(s.got, tolerance)
+	return s.MemoryStore.GetKabupatenFeatureCollection(ctx, propinsiID, tolerance, fn)
+}
+
+func (s *toleranceStore) ExportLevel(ctx context.Context, level, parentID string, tolerance float64, encoding repositories.GeometryEncoding, fn func(models.ExportRow) error) error {
+	s.got = append(s.got, tolerance)
+	return s.MemoryStore.ExportLevel(ctx, level, parentID, tolerance, encoding, fn)
+}
+
+func TestSimplifyToleranceQuantized(t *testing.T) {
+	store := &toleranceStore{MemoryStore: repositories.NewMemoryStore(repositories.SampleFixtures())}
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.HTTPErrorHandler
+	routes.Setup(e, store, config.Default(), &health.State{})
+
+	for _, target := range []string{
+		"/geojson/kabupaten/3201?simplify=0.01",
+		"/geojson/propinsi/32/kabupaten?simplify=0.01",
+		"/export/kabupaten?parent=32&simplify=0.01",
+		"/geojson/kabupaten/3201?zoom=8",
+	} {
+		if rec := doRequest(t, e, http.MethodGet, target, "", ""); rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want 200", target, rec.Code)
+		}
+	}
+
+	// 0.01 dibulatkan ke bawah ke tolerance zoom 8 di semua endpoint
+	want := repositories.ZoomTolerance(8)
+	if len(store.got) != 4 {
+		t.Fatalf("store called %d times, want 4", len(store.got))
+	}
+	for i, got := range store.got {
+		if got != want {
+			t.Errorf("call %d: tolerance = %g, want %g", i, got, want)
+		}
+	}
+
+	for _, tt := range []struct{ in, want float64 }{
+		{0, 0}, {1, repositories.ZoomTolerance(1)}, {0.5, repositories.ZoomTolerance(2)},
+		{repositories.ZoomTolerance(22), repositories.ZoomTolerance(22)}, {1e-9, 0},
+	} {
+		if got := repositories.QuantizeTolerance(tt.in); got != tt.want {
+			t.Errorf("QuantizeTolerance(%g) = %g, want %g", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGeoJSONChildrenUnknownParent(t *testing.T) {
+	e := newTestServer()
+
+	for _, target := range []string{
+		"/geojson/propinsi/99/kabupaten",
+		"/geojson/kabupaten/9999/kecamatan",
+		"/geojson/kecamatan/999/kelurahan",
+	} {
+		t.Run(target, func(t *testing.T) {
+			assertError(t, doRequest(t, e, http.MethodGet, target, "", ""), http.StatusNotFound, "NOT_FOUND")
+		})
+	}
+}
+
+func TestTiles(t *testing.T) {
+	e := newTestServer()
+
+	rec := doRequest(t, e, http.MethodGet, "/tiles/auto/tile.json", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("tile.json status = %d, want 200", rec.Code)
+	}
+	tileJSON := decode[models.TileJSON](t, rec)
+	if len(tileJSON.Tiles) != 1 || !strings.HasSuffix(tileJSON.Tiles[0], "/tiles/auto/{z}/{x}/{y}.mvt") {
+		t.Errorf("tiles = %v", tileJSON.Tiles)
+	}
+	if len(tileJSON.VectorLayers) != 4 {
+		t.Errorf("auto should describe 4 vector layers, got %d", len(tileJSON.VectorLayers))
+	}
+
+	rec = doRequest(t, e, http.MethodGet, "/tiles/kabupaten/7/104/66.mvt", "", "")
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("empty tile status = %d, want 204", rec.Code)
+	}
+
+	for _, target := range []string{
+		"/tiles/desa/7/104/66.mvt",
+		"/tiles/desa/tile.json",
+		"/tiles/kabupaten/7/104/66",
+		"/tiles/kabupaten/2/4/0.mvt",
+		"/tiles/kabupaten/23/0/0.mvt",
+	} {
+		rec = doRequest(t, e, http.MethodGet, target, "", "")
+		assertError(t, rec, http.StatusBadRequest, "INVALID_ARGUMENT")
+	}
+}
+
+func TestReverseGeocode(t *testing.T) {
+	e := newTestServer()
+
+	rec := doRequest(t, e, http.MethodGet, "/reverse?lat=-6.21&lon=106.71", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if kel := decode[models.Kelurahan](t, rec); kel.KdKelurahan != "3171010001" || kel.NmPropinsi != "DKI Jakarta" {
+		t.Errorf("got %+v, want Jagakarsa", kel)
+	}
+
+	// Titik di sudut yang dipakai bersama Nanggung dan Kalibunder diselesaikan ke kode terkecil
+	rec = doRequest(t, e, http.MethodGet, "/reverse?lat=-6.35&lon=106.55", "", "")
+	if kel := decode[models.Kelurahan](t, rec); kel.KdKelurahan != "3201010001" {
+		t.Errorf("border point resolved to %s, want 3201010001", kel.KdKelurahan)
+	}
+
+	rec = doRequest(t, e, http.MethodGet, "/reverse?lat=51.5&lon=-0.12", "", "")
+	assertError(t, rec, http.StatusNotFound, "NOT_FOUND")
+
+	for _, target := range []string{"/reverse", "/reverse?lat=abc&lon=106.7", "/reverse?lat=-95&lon=106.7"} {
+		rec = doRequest(t, e, http.MethodGet, target, "", "")
+		assertError(t, rec, http.StatusBadRequest, "INVALID_ARGUMENT")
+	}
+}
+
+func TestBatchReverseGeocode(t *testing.T) {
+	e := newTestServer()
+
+	wantResults := func(t *testing.T, rec *httptest.ResponseRecorder) {
+		t.Helper()
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+		}
+
+		results := decode[[]models.ReverseResult](t, rec)
+		if len(results) != 4 {
+			t.Fatalf("got %d results, want 4", len(results))
+		}
+		if results[0].ID != "a" || results[0].Result == nil || results[0].Result.KdKelurahan != "3171010001" {
+			t.Errorf("results[0] = %+v, want Jagakarsa", results[0])
+		}
+		for i, want := range []models.ReverseResult{{ID: "b", Code: "NOT_FOUND"}, {ID: "c", Code: "INVALID_ARGUMENT"}, {ID: "d", Code: "INVALID_ARGUMENT"}} {
+			if r := results[i+1]; r.ID != want.ID || r.Result != nil || r.Code != want.Code || r.Error == "" {
+				t.Errorf("results[%d] = %+v, want per-item %s error", i+1, r, want.Code)
+			}
+		}
+	}
+
+	t.Run("json", func(t *testing.T) {
+		body := `[
+			{"id": "a", "lat": -6.21, "lon": 106.71},
+			{"id": "b", "lat": 0, "lon": 0},
+			{"id": "c", "lon": 106.71},
+			{"id": "d", "lat": 100, "lon": 106.71}
+		]`
+		wantResults(t, doRequest(t, e, http.MethodPost, "/reverse/batch", echo.MIMEApplicationJSON, body))
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		body := `{"id": "a", "lat": -6.21, "lon": 106.71}
+{"id": "b", "lat": 0, "lon": 0}
+{"id": "c", "lon": 106.71}
+{"id": "d", "lat": 100, "lon": 106.71}
+`
+		wantResults(t, doRequest(t, e, http.MethodPost, "/reverse/batch", "application/x-ndjson", body))
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		rec := doRequest(t, e, http.MethodPost, "/reverse/batch", echo.MIMEApplicationJSON, `{"id": "a"}`)
+		assertError(t, rec, http.StatusBadRequest, "INVALID_ARGUMENT")
+	})
+
+	t.Run("too many points", func(t *testing.T) {
+		body := "[" + strings.Repeat(`{"lat":0,"lon":0},`, 50000) + `{"lat":0,"lon":0}]`
+		rec := doRequest(t, e, http.MethodPost, "/reverse/batch", echo.MIMEApplicationJSON, body)
+		assertError(t, rec, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE")
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		body := `[{"id": "` + strings.Repeat("a", 17<<20) + `"}]`
+		rec := doRequest(t, e, http.MethodPost, "/reverse/batch", echo.MIMEApplicationJSON, body)
+		assertError(t, rec, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE")
+	})
+}
+
+// failingBatchStore menggagalkan fail panggilan BatchReverseGeocode pertama dengan err
+type failingBatchStore struct {
+	*repositories.MemoryStore
+	fail  int
+	err   error
+	calls int
+}
+
+func (s *failingBatchStore) BatchReverseGeocode(ctx context.Context, lats, lons []float64) ([]*models.Kelurahan, error) {
+	s.calls++
+	if s.calls <= s.fail {
+		return nil, s.err
+	}
+	return s.MemoryStore.BatchReverseGeocode(ctx, lats, lons)
+}
+
+func TestBatchReverseGeocodeChunkErrors(t *testing.T) {
+	// 1500 titik di Jagakarsa menjadi dua chunk: 1000 lalu 500
+	body := "[" + strings.Repeat(`{"lat":-6.21,"lon":106.71},`, 1499) + `{"lat":-6.21,"lon":106.71}]`
+	timeout := apperror.New(apperror.CodeTimeout, "Database query timed out")
+
+	serve := func(store repositories.LocationStore, ctx context.Context) *httptest.ResponseRecorder {
+		e := echo.New()
+		e.HTTPErrorHandler = handlers.HTTPErrorHandler
+		e.Use(middleware.RequestID())
+		routes.Setup(e, store, config.Default(), &health.State{})
+
+		req := httptest.NewRequest(http.MethodPost, "/reverse/batch", strings.NewReader(body)).WithContext(ctx)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("one chunk failed", func(t *testing.T) {
+		store := &failingBatchStore{MemoryStore: repositories.NewMemoryStore(repositories.SampleFixtures()), fail: 1, err: timeout}
+		rec := serve(store, context.Background())
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+		}
+
+		results := decode[[]models.ReverseResult](t, rec)
+		if r := results[0]; r.Code != "TIMEOUT" || r.Error != "Database query timed out" || r.Result != nil {
+			t.Errorf("results[0] = %+v, want TIMEOUT from the failed chunk", r)
+		}
+		if r := results[1000]; r.Code != "" || r.Result == nil {
+			t.Errorf("results[1000] = %+v, want a result from the second chunk", r)
+		}
+	})
+
+	t.Run("every chunk failed", func(t *testing.T) {
+		store := &failingBatchStore{MemoryStore: repositories.NewMemoryStore(repositories.SampleFixtures()), fail: 2, err: timeout}
+		assertError(t, serve(store, context.Background()), http.StatusGatewayTimeout, "TIMEOUT")
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		canceled := apperror.New(apperror.CodeCanceled, "Request canceled by client")
+		store := &failingBatchStore{MemoryStore: repositories.NewMemoryStore(repositories.SampleFixtures()), fail: 2, err: canceled}
+		assertError(t, serve(store, ctx), apperror.StatusClientClosedRequest, "CANCELED")
+		if store.calls != 1 {
+			t.Errorf("BatchReverseGeocode called %d times, want the batch to stop after the canceled chunk", store.calls)
+		}
+	})
+}
+
+func TestExport(t *testing.T) {
+	e := newTestServer()
+
+	t.Run("geojson", func(t *testing.T) {
+		rec := doRequest(t, e, http.MethodGet, "/export/kabupaten?parent=32", "", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+		}
+		if got := rec.Header().Get(echo.HeaderContentDisposition); got != "attachment; filename=kabupaten-32.geojson" {
+			t.Errorf("Content-Disposition = %q", got)
+		}
+
+		fc := decode[models.GeoJSONFeatureCollection](t, rec)
+		if len(fc.Features) != 2 || fc.Features[0].Properties.ID != 3201 || fc.Features[1].Properties.NmPropinsi != "Jawa Barat" {
+			t.Errorf("features = %+v, want Bogor and Sukabumi with their province", fc.Features)
+		}
+	})
+
+	t.Run("geojsonseq", func(t *testing.T) {
+		rec := doRequest(t, e, http.MethodGet, "/export/propinsi?format=geojsonseq", "", "")
+		lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+		if rec.Code != http.StatusOK || len(lines) != 3 {
+			t.Fatalf("status = %d, got %d lines, want 200 and 3", rec.Code, len(lines))
+		}
+		for _, line := range lines {
+			var f models.GeoJSONFeature
+			if err := json.Unmarshal([]byte(line), &f); err != nil || f.Geometry == nil {
+				t.Errorf("line %q: %v", line, err)
+			}
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		rec := doRequest(t, e, http.MethodGet, "/export/kecamatan?format=csv&parent=3201", "", "")
+		if rec.Code != http.StatusOK || rec.Header().Get(echo.HeaderContentType) != "text/csv; charset=utf-8" {
+			t.Fatalf("status = %d, Content-Type = %q", rec.Code, rec.Header().Get(echo.HeaderContentType))
+		}
+
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		if lines[0] != "kd_propinsi,nm_propinsi,kd_kabupaten,nm_kabupaten,kd_kecamatan,nm_kecamatan,wkt" {
+			t.Errorf("header = %q", lines[0])
+		}
+		if len(lines) != 3 || !strings.HasPrefix(lines[1], "32,Jawa Barat,3201,Kabupaten Bogor,3201010,Nanggung,\"MULTIPOLYGON(((") {
+			t.Errorf("rows = %q", lines[1:])
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		req := httptest.NewRequest(http.MethodGet, "/export/propinsi", nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assertError(t, rec, apperror.StatusClientClosedRequest, "CANCELED")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, target := range []string{"/export/desa", "/export/propinsi?parent=32", "/export/kabupaten?format=xml", "/export/kabupaten?zoom=99",
+			"/export/kabupaten?parent=32%22%3B%20x%3D", "/export/kecamatan?parent=..%2F3201", "/export/kecamatan?parent=32.01"} {
+			assertError(t, doRequest(t, e, http.MethodGet, target, "", ""), http.StatusBadRequest, "INVALID_ARGUMENT")
+		}
+	})
+
+	t.Run("non-numeric code", func(t *testing.T) {
+		e := echo.New()
+		e.HTTPErrorHandler = handlers.HTTPErrorHandler
+		e.Use(middleware.RequestID())
+		fixtures := repositories.MemoryFixtures{Propinsi: []repositories.MemoryRegion{{Code: "32.01", Name: "Jawa Barat"}}}
+		routes.Setup(e, repositories.NewMemoryStore(fixtures), config.Default(), &health.State{})
+
+		assertError(t, doRequest(t, e, http.MethodGet, "/export/propinsi", "", ""), http.StatusInternalServerError, "INTERNAL")
+	})
+}
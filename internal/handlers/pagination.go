@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"location-svc/internal/apperror"
+	"location-svc/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// defaultPageLimit dipakai jika query param limit tidak diisi
+	defaultPageLimit = 50
+	// maxPageLimit adalah batas atas limit; nilai yang lebih besar dipotong ke batas ini
+	maxPageLimit = 1000
+)
+
+// parsePageParams membaca query param limit, offset dan cursor
+func parsePageParams(c echo.Context) (models.PageParams, error) {
+	page := models.PageParams{
+		Limit:  defaultPageLimit,
+		Cursor: c.QueryParam("cursor"),
+	}
+
+	if s := c.QueryParam("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit < 1 {
+			return page, apperror.InvalidArgument("limit must be a positive integer")
+		}
+		page.Limit = min(limit, maxPageLimit)
+	}
+
+	if s := c.QueryParam("offset"); s != "" {
+		offset, err := strconv.Atoi(s)
+		if err != nil || offset < 0 {
+			return page, apperror.InvalidArgument("offset must be a non-negative integer")
+		}
+		page.Offset = offset
+	}
+
+	if page.Cursor != "" && page.Offset > 0 {
+		return page, apperror.InvalidArgument("offset cannot be combined with cursor")
+	}
+
+	return page, nil
+}
+
+// respondPage mengirim envelope page dan header Link rel="next" jika masih ada halaman berikutnya
+func respondPage[T any](c echo.Context, page *models.Page[T]) error {
+	if page.NextCursor != "" {
+		next := *c.Request().URL
+		query := next.Query()
+		query.Del("offset")
+		query.Set("cursor", page.NextCursor)
+		next.RawQuery = query.Encode()
+
+		c.Response().Header().Set("Link", "<"+next.RequestURI()+`>; rel="next"`)
+	}
+
+	c.Response().Header().Set("X-Total-Count", strconv.Itoa(page.Total))
+	return c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"location-svc/internal/apperror"
+	"location-svc/internal/models"
+	"location-svc/internal/repositories"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// indonesiaBounds adalah bounding box wilayah Indonesia (west, south, east, north)
+var indonesiaBounds = []float64{94.0, -11.5, 141.5, 6.5}
+
+// GetTile godoc
+// @Summary Get Mapbox Vector Tile
+// @Description Get an MVT tile of administrative boundaries. Use level "auto" to select propinsi (z0-5), kabupaten (z6-8), kecamatan (z9-11) or kelurahan (z12+) by zoom. Feature properties carry kd_*/nm_* codes and names of the region and its parents.
+// @Tags tiles
+// @Produce application/vnd.mapbox-vector-tile
+// @Param level path string true "Level: propinsi, kabupaten, kecamatan, kelurahan or auto"
+// @Param z path integer true "Zoom"
+// @Param x path integer true "Tile column"
+// @Param y path string true "Tile row with .mvt extension, e.g. 12.mvt"
+// @Success 200 {file} binary
+// @Success 204 "Empty tile"
+// @Failure 400 {object} models.ErrorResponse
+// @Router /tiles/{level}/{z}/{x}/{y}.mvt [get]
+func (h *LocationHandler) GetTile(c echo.Context) error {
+	level := c.Param("level")
+	if !repositories.IsTileLevel(level) {
+		return apperror.InvalidArgument("level must be one of propinsi, kabupaten, kecamatan, kelurahan or auto")
+	}
+
+	yStr, ok := strings.CutSuffix(c.Param("y"), ".mvt")
+	z, errZ := strconv.Atoi(c.Param("z"))
+	x, errX := strconv.Atoi(c.Param("x"))
+	y, errY := strconv.Atoi(yStr)
+
+	if !ok || errZ != nil || errX != nil || errY != nil ||
+		z < 0 || z > repositories.MaxTileZoom || x < 0 || y < 0 || x >= 1<<z || y >= 1<<z {
+		return apperror.InvalidArgument("Invalid tile coordinate")
+	}
+
+	tile, err := h.repo.GetTile(c.Request().Context(), level, z, x, y)
+	if err != nil {
+		return err
+	}
+
+	if len(tile) == 0 {
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	return c.Blob(http.StatusOK, "application/vnd.mapbox-vector-tile", tile)
+}
+
+// GetTileJSON godoc
+// @Summary Get TileJSON descriptor
+// @Description Get a TileJSON 3.0.0 descriptor for the vector tiles of a level, usable directly as a Mapbox vector source URL
+// @Tags tiles
+// @Produce json
+// @Param level path string true "Level: propinsi, kabupaten, kecamatan, kelurahan or auto"
+// @Success 200 {object} models.TileJSON
+// @Failure 400 {object} models.ErrorResponse
+// @Router /tiles/{level}/tile.json [get]
+func (h *LocationHandler) GetTileJSON(c echo.Context) error {
+	level := c.Param("level")
+	if !repositories.IsTileLevel(level) {
+		return apperror.InvalidArgument("level must be one of propinsi, kabupaten, kecamatan, kelurahan or auto")
+	}
+
+	baseURL := c.Scheme() + "://" + c.Request().Host
+
+	return c.JSON(http.StatusOK, models.TileJSON{
+		TileJSON:     "3.0.0",
+		Name:         "location-svc " + level,
+		Scheme:       "xyz",
+		Tiles:        []string{baseURL + "/tiles/" + level + "/{z}/{x}/{y}.mvt"},
+		MinZoom:      0,
+		MaxZoom:      repositories.MaxTileZoom,
+		Bounds:       indonesiaBounds,
+		VectorLayers: repositories.TileVectorLayers(level),
+	})
+}
@@ -0,0 +1,106 @@
+// Package health menyimpan status readiness service yang dilaporkan ke load balancer
+// dan menjalankan pemeriksaan readiness untuk /readyz.
+package health
+
+import (
+	"context"
+	"location-svc/internal/models"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// StatusOK, StatusFail dan StatusShuttingDown adalah nilai status di HealthReport
+	StatusOK           = "OK"
+	StatusFail         = "FAIL"
+	StatusShuttingDown = "SHUTTING_DOWN"
+)
+
+// Check adalah satu pemeriksaan readiness. Run mengembalikan detail yang ditampilkan di
+// laporan (boleh nil) dan error jika dependency belum siap.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) (detail interface{}, err error)
+}
+
+// State adalah status readiness service. Zero value berarti siap menerima traffic tanpa pemeriksaan.
+type State struct {
+	shuttingDown atomic.Bool
+	timeout      time.Duration
+	checks       []Check
+}
+
+// NewState creates new instance of State dengan pemeriksaan readiness yang masing-masing
+// dibatasi timeout; timeout 0 berarti tanpa batas selain context request
+func NewState(timeout time.Duration, checks ...Check) *State {
+	return &State{timeout: timeout, checks: checks}
+}
+
+// StartShutdown menandai service sedang berhenti sehingga readiness gagal,
+// sementara request yang sudah masuk tetap dilayani sampai selesai
+func (s *State) StartShutdown() {
+	s.shuttingDown.Store(true)
+}
+
+// ShuttingDown melaporkan apakah StartShutdown sudah dipanggil
+func (s *State) ShuttingDown() bool {
+	return s.shuttingDown.Load()
+}
+
+// Ready menjalankan semua pemeriksaan secara paralel dan mengembalikan laporannya.
+// Status laporan OK hanya jika semua pemeriksaan berhasil dan service tidak sedang shutdown;
+// saat shutdown pemeriksaan tidak dijalankan.
+func (s *State) Ready(ctx context.Context) models.HealthReport {
+	start := time.Now()
+	if s.ShuttingDown() {
+		return models.HealthReport{Status: StatusShuttingDown, LatencyMs: sinceMs(start)}
+	}
+
+	results := make([]models.HealthCheck, len(s.checks))
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = s.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := models.HealthReport{Status: StatusOK}
+	if len(s.checks) > 0 {
+		report.Checks = make(map[string]models.HealthCheck, len(s.checks))
+	}
+	for i, check := range s.checks {
+		report.Checks[check.Name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	report.LatencyMs = sinceMs(start)
+	return report
+}
+
+// run menjalankan satu pemeriksaan dengan timeout dan mencatat latency-nya
+func (s *State) run(ctx context.Context, check Check) models.HealthCheck {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	detail, err := check.Run(ctx)
+	result := models.HealthCheck{Status: StatusOK, LatencyMs: sinceMs(start), Detail: detail}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// sinceMs mengembalikan waktu sejak start dalam milidetik dengan presisi mikrodetik
+func sinceMs(start time.Time) float64 {
+	return float64(time.Since(start).Microseconds()) / 1000
+}
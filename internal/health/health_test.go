@@ -0,0 +1,48 @@
+package health_test
+
+import (
+	"context"
+	"location-svc/internal/health"
+	"testing"
+	"time"
+)
+
+func TestReadyRunsChecksConcurrentlyWithTimeout(t *testing.T) {
+	slow := func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	state := health.NewState(50*time.Millisecond,
+		health.Check{Name: "slow1", Run: slow},
+		health.Check{Name: "slow2", Run: slow},
+		health.Check{Name: "fast", Run: func(ctx context.Context) (interface{}, error) { return "ok", nil }},
+	)
+
+	start := time.Now()
+	report := state.Ready(context.Background())
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Ready took %s, want checks to run in parallel within the timeout", elapsed)
+	}
+
+	if report.Status != health.StatusFail || len(report.Checks) != 3 {
+		t.Fatalf("report = %+v, want FAIL with 3 checks", report)
+	}
+	if got := report.Checks["slow1"]; got.Status != health.StatusFail || got.Error != context.DeadlineExceeded.Error() || got.LatencyMs < 50 {
+		t.Errorf("slow1 = %+v, want FAIL after the timeout", got)
+	}
+	if got := report.Checks["fast"]; got.Status != health.StatusOK || got.Detail != "ok" {
+		t.Errorf("fast = %+v, want OK with detail", got)
+	}
+}
+
+func TestReadyZeroValue(t *testing.T) {
+	var state health.State
+	if report := state.Ready(context.Background()); report.Status != health.StatusOK || report.Checks != nil {
+		t.Errorf("report = %+v, want OK without checks", report)
+	}
+
+	state.StartShutdown()
+	if report := state.Ready(context.Background()); report.Status != health.StatusShuttingDown {
+		t.Errorf("status = %q, want %q", report.Status, health.StatusShuttingDown)
+	}
+}
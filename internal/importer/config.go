@@ -0,0 +1,72 @@
+// Package importer memuat data batas wilayah dari GeoJSON, GeoJSONSeq atau shapefile ke database
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config memetakan atribut sumber ke kolom kd_*/nm_* sebuah level
+type Config struct {
+	// Level adalah level tujuan: propinsi, kabupaten, kecamatan atau kelurahan
+	Level string `json:"level"`
+	// CodeField adalah atribut sumber untuk kolom kd_<level>
+	CodeField string `json:"code_field"`
+	// NameField adalah atribut sumber untuk kolom nm_<level>
+	NameField string `json:"name_field"`
+	// ParentField adalah atribut sumber untuk kode induk, wajib kecuali level propinsi
+	ParentField string `json:"parent_field"`
+	// SourceSRID memaksa SRID sumber; 0 berarti dibaca dari sumber (crs/.prj) atau EPSG:4326
+	SourceSRID int `json:"source_srid"`
+	// Upsert mempertahankan baris yang tidak ada di sumber; defaultnya isi level diganti penuh
+	Upsert bool `json:"upsert"`
+}
+
+// levelTable mendeskripsikan tabel tujuan sebuah level
+type levelTable struct {
+	table     string
+	codeCol   string
+	nameCol   string
+	parentCol string
+	parent    string
+}
+
+var levelTables = map[string]levelTable{
+	"propinsi":  {table: "propinsi", codeCol: "kd_propinsi", nameCol: "nm_propinsi"},
+	"kabupaten": {table: "kabupaten", codeCol: "kd_kabupaten", nameCol: "nm_kabupaten", parentCol: "kd_propinsi", parent: "propinsi"},
+	"kecamatan": {table: "kecamatan", codeCol: "kd_kecamatan", nameCol: "nm_kecamatan", parentCol: "kd_kabupaten", parent: "kabupaten"},
+	"kelurahan": {table: "kelurahan", codeCol: "kd_kelurahan", nameCol: "nm_kelurahan", parentCol: "kd_kecamatan", parent: "kecamatan"},
+}
+
+// LoadConfig membaca Config dari file JSON
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Validate memastikan level dikenal dan semua atribut wajib terisi
+func (c Config) Validate() error {
+	level, ok := levelTables[c.Level]
+	if !ok {
+		return fmt.Errorf("unknown level %q, must be propinsi, kabupaten, kecamatan or kelurahan", c.Level)
+	}
+	if c.CodeField == "" || c.NameField == "" {
+		return fmt.Errorf("code_field and name_field are required")
+	}
+	if level.parentCol != "" && c.ParentField == "" {
+		return fmt.Errorf("parent_field is required for level %s", c.Level)
+	}
+	if c.SourceSRID < 0 {
+		return fmt.Errorf("source_srid must be a positive EPSG code")
+	}
+	return nil
+}
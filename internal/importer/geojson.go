@@ -0,0 +1,178 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// geoJSONFeature adalah bentuk JSON sebuah Feature
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Properties map[string]interface{} `json:"properties"`
+	Geometry   json.RawMessage        `json:"geometry"`
+}
+
+// geoJSONCRS adalah member crs GeoJSON 2008 yang masih dipakai banyak rilis data
+type geoJSONCRS struct {
+	Properties struct {
+		Name string `json:"name"`
+	} `json:"properties"`
+}
+
+func (f geoJSONFeature) feature(n int) (*Feature, error) {
+	if f.Type != "Feature" {
+		return nil, fmt.Errorf("feature %d: type is %q, want Feature", n, f.Type)
+	}
+	if len(f.Geometry) == 0 || bytes.Equal(f.Geometry, []byte("null")) {
+		return nil, fmt.Errorf("feature %d: geometry is null", n)
+	}
+	return &Feature{Properties: f.Properties, Geometry: f.Geometry}, nil
+}
+
+// geoJSONSource membaca FeatureCollection secara streaming tanpa memuat seluruh file ke memori
+type geoJSONSource struct {
+	file       *os.File
+	dec        *json.Decoder
+	srid       int
+	n          int
+	started    bool
+	inFeatures bool
+}
+
+func newGeoJSONSource(f *os.File) *geoJSONSource {
+	dec := json.NewDecoder(bufio.NewReaderSize(f, 1<<20))
+	dec.UseNumber()
+	return &geoJSONSource{file: f, dec: dec}
+}
+
+func (s *geoJSONSource) Next() (*Feature, error) {
+	if !s.started {
+		s.started = true
+		if err := s.expectDelim('{'); err != nil {
+			return nil, err
+		}
+	}
+
+	for !s.inFeatures {
+		if !s.dec.More() {
+			return nil, io.EOF
+		}
+		if err := s.readMember(); err != nil {
+			return nil, err
+		}
+	}
+
+	if !s.dec.More() {
+		// Akhir array features; member lain (mis. crs) mungkin masih ada sesudahnya
+		if err := s.expectDelim(']'); err != nil {
+			return nil, err
+		}
+		s.inFeatures = false
+		return s.Next()
+	}
+
+	s.n++
+	var f geoJSONFeature
+	if err := s.dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("feature %d: %w", s.n, err)
+	}
+	return f.feature(s.n)
+}
+
+// readMember membaca satu member object FeatureCollection di level teratas
+func (s *geoJSONSource) readMember() error {
+	tok, err := s.dec.Token()
+	if err != nil {
+		return err
+	}
+	key, _ := tok.(string)
+
+	switch key {
+	case "type":
+		var t string
+		if err := s.dec.Decode(&t); err != nil {
+			return err
+		}
+		if t != "FeatureCollection" {
+			return fmt.Errorf("GeoJSON type is %q, want FeatureCollection", t)
+		}
+	case "crs":
+		var crs geoJSONCRS
+		if err := s.dec.Decode(&crs); err != nil {
+			return err
+		}
+		s.srid = parseCRSName(crs.Properties.Name)
+	case "features":
+		if err := s.expectDelim('['); err != nil {
+			return err
+		}
+		s.inFeatures = true
+	default:
+		var skip json.RawMessage
+		return s.dec.Decode(&skip)
+	}
+	return nil
+}
+
+func (s *geoJSONSource) expectDelim(want json.Delim) error {
+	tok, err := s.dec.Token()
+	if err != nil {
+		return fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("invalid GeoJSON: expected %q, got %v", want, tok)
+	}
+	return nil
+}
+
+func (s *geoJSONSource) SRID() int    { return s.srid }
+func (s *geoJSONSource) Rings() bool  { return false }
+func (s *geoJSONSource) Close() error { return s.file.Close() }
+
+// maxSeqLine adalah ukuran maksimum satu feature GeoJSONSeq (geometri kabupaten bisa puluhan MB)
+const maxSeqLine = 256 << 20
+
+// geoJSONSeqSource membaca satu Feature per baris (RFC 8142, dengan atau tanpa record separator)
+type geoJSONSeqSource struct {
+	file    *os.File
+	scanner *bufio.Scanner
+	n       int
+}
+
+func newGeoJSONSeqSource(f *os.File) *geoJSONSeqSource {
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64<<10), maxSeqLine)
+	return &geoJSONSeqSource{file: f, scanner: scanner}
+}
+
+func (s *geoJSONSeqSource) Next() (*Feature, error) {
+	for s.scanner.Scan() {
+		line := bytes.TrimSpace(bytes.TrimLeft(s.scanner.Bytes(), "\x1e"))
+		if len(line) == 0 {
+			continue
+		}
+
+		s.n++
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+
+		var f geoJSONFeature
+		if err := dec.Decode(&f); err != nil {
+			return nil, fmt.Errorf("feature %d: %w", s.n, err)
+		}
+		return f.feature(s.n)
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+func (s *geoJSONSeqSource) SRID() int    { return 0 }
+func (s *geoJSONSeqSource) Rings() bool  { return false }
+func (s *geoJSONSeqSource) Close() error { return s.file.Close() }
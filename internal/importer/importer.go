@@ -0,0 +1,264 @@
+package importer
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// maxReported adalah jumlah maksimum kode yang disebut dalam pesan validasi
+const maxReported = 10
+
+// Result merangkum hasil import satu level
+type Result struct {
+	Level    string
+	SRID     int
+	Rows     int
+	Inserted int
+	Updated  int
+	Deleted  int
+}
+
+// Import memuat semua feature dari src ke level cfg.Level dalam satu transaksi.
+// Feature disalin ke tabel staging, direproyeksi ke EPSG:4326 dan divalidasi (kode unik,
+// kode induk ada, geometri tidak kosong) sebelum isi tabel level ditukar. Tanpa cfg.Upsert,
+// baris yang tidak ada di sumber dihapus. Jika satu langkah gagal tidak ada perubahan tersimpan.
+func Import(db *sql.DB, src Source, cfg Config) (*Result, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	level := levelTables[cfg.Level]
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`CREATE TEMP TABLE import_staging (
+		n INTEGER NOT NULL,
+		code TEXT NOT NULL,
+		name TEXT NOT NULL,
+		parent TEXT,
+		geojson TEXT NOT NULL
+	) ON COMMIT DROP`)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := copyFeatures(tx, src, cfg, level)
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		return nil, errors.New("source contains no features")
+	}
+
+	srid := cfg.SourceSRID
+	if srid == 0 {
+		srid = src.SRID()
+	}
+	if srid == 0 {
+		if src.Rings() {
+			return nil, errors.New("shapefile has no EPSG code in its .prj, set source_srid")
+		}
+		// RFC 7946: GeoJSON tanpa crs memakai WGS 84
+		srid = 4326
+	}
+
+	if err := validateStaging(tx, level); err != nil {
+		return nil, err
+	}
+
+	result := &Result{Level: cfg.Level, SRID: srid, Rows: rows}
+	if err := swapLevel(tx, level, srid, src.Rings(), !cfg.Upsert, result); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// copyFeatures menyalin feature ke import_staging memakai COPY dan mengembalikan jumlah baris
+func copyFeatures(tx *sql.Tx, src Source, cfg Config, level levelTable) (int, error) {
+	stmt, err := tx.Prepare(pq.CopyIn("import_staging", "n", "code", "name", "parent", "geojson"))
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for {
+		f, err := src.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			stmt.Close()
+			return 0, err
+		}
+		n++
+
+		code := propertyString(f.Properties[cfg.CodeField])
+		name := propertyString(f.Properties[cfg.NameField])
+		if code == "" || name == "" {
+			stmt.Close()
+			return 0, fmt.Errorf("feature %d: %s and %s must not be empty", n, cfg.CodeField, cfg.NameField)
+		}
+
+		var parent interface{}
+		if level.parentCol != "" {
+			p := propertyString(f.Properties[cfg.ParentField])
+			if p == "" {
+				stmt.Close()
+				return 0, fmt.Errorf("feature %d (%s): %s must not be empty", n, code, cfg.ParentField)
+			}
+			parent = p
+		}
+
+		if _, err := stmt.Exec(n, code, name, parent, string(f.Geometry)); err != nil {
+			stmt.Close()
+			return 0, err
+		}
+	}
+
+	// Exec tanpa argumen mengirim sisa buffer COPY
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		return 0, err
+	}
+	return n, stmt.Close()
+}
+
+// validateStaging memastikan kode unik dan semua kode induk sudah ada di tabel induk
+func validateStaging(tx *sql.Tx, level levelTable) error {
+	duplicates, err := queryCodes(tx, `SELECT code FROM import_staging GROUP BY code HAVING COUNT(*) > 1 ORDER BY code`)
+	if err != nil {
+		return err
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("duplicate codes in source: %s", reportCodes(duplicates))
+	}
+
+	if level.parentCol == "" {
+		return nil
+	}
+
+	missing, err := queryCodes(tx, `SELECT DISTINCT s.parent FROM import_staging s
+		WHERE NOT EXISTS (SELECT 1 FROM `+level.parent+` p WHERE p.`+level.parentCol+` = s.parent)
+		ORDER BY s.parent`)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("parent codes not found in %s (import it first): %s", level.parent, reportCodes(missing))
+	}
+	return nil
+}
+
+// swapLevel membangun geometri final (reproyeksi dan perbaikan validitas) lalu mengganti isi tabel level
+func swapLevel(tx *sql.Tx, level levelTable, srid int, rings, prune bool, result *Result) error {
+	geom := "ST_GeomFromGeoJSON(geojson)"
+	if rings {
+		geom = "ST_BuildArea(" + geom + ")"
+	}
+
+	_, err := tx.Exec(`CREATE TEMP TABLE import_geom ON COMMIT DROP AS
+		SELECT n, code, name, parent,
+			ST_Multi(ST_CollectionExtract(ST_MakeValid(ST_Transform(ST_SetSRID(`+geom+`, $1), 4326)), 3)) AS geom
+		FROM import_staging`, srid)
+	if err != nil {
+		return fmt.Errorf("building geometries: %w", err)
+	}
+
+	empty, err := queryCodes(tx, `SELECT code FROM import_geom WHERE geom IS NULL OR ST_IsEmpty(geom) ORDER BY n`)
+	if err != nil {
+		return err
+	}
+	if len(empty) > 0 {
+		return fmt.Errorf("features without a polygon geometry: %s", reportCodes(empty))
+	}
+
+	columns := level.codeCol + ", " + level.nameCol + ", geom"
+	values := "code, name, geom::geometry(MULTIPOLYGON, 4326)"
+	update := level.nameCol + " = EXCLUDED." + level.nameCol + ", geom = EXCLUDED.geom"
+	if level.parentCol != "" {
+		columns += ", " + level.parentCol
+		values += ", parent"
+		update += ", " + level.parentCol + " = EXCLUDED." + level.parentCol
+	}
+
+	rows, err := tx.Query(`INSERT INTO ` + level.table + ` (` + columns + `)
+		SELECT ` + values + ` FROM import_geom ORDER BY n
+		ON CONFLICT (` + level.codeCol + `) DO UPDATE SET ` + update + `
+		RETURNING xmax = 0`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var inserted bool
+		if err := rows.Scan(&inserted); err != nil {
+			rows.Close()
+			return err
+		}
+		if inserted {
+			result.Inserted++
+		} else {
+			result.Updated++
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if !prune {
+		return nil
+	}
+
+	res, err := tx.Exec(`DELETE FROM ` + level.table + ` t
+		WHERE NOT EXISTS (SELECT 1 FROM import_geom s WHERE s.code = t.` + level.codeCol + `)`)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return fmt.Errorf("cannot remove %s rows missing from the source because child regions still reference them (%s); use upsert or import the child level first", level.table, pqErr.Detail)
+	}
+	if err != nil {
+		return err
+	}
+
+	deleted, err := res.RowsAffected()
+	result.Deleted = int(deleted)
+	return err
+}
+
+// queryCodes menjalankan query yang mengembalikan satu kolom kode
+func queryCodes(tx *sql.Tx, query string) ([]string, error) {
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var codes []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, rows.Err()
+}
+
+// reportCodes meringkas daftar kode untuk pesan error
+func reportCodes(codes []string) string {
+	if len(codes) <= maxReported {
+		return strings.Join(codes, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(codes[:maxReported], ", "), len(codes)-maxReported)
+}
@@ -0,0 +1,253 @@
+package importer_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"location-svc/internal/importer"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readAll membaca semua feature dari file dan mengembalikan source yang sudah habis dibaca
+func readAll(t *testing.T, path, format string) ([]*importer.Feature, importer.Source) {
+	t.Helper()
+
+	src, err := importer.Open(path, format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { src.Close() })
+
+	var features []*importer.Feature
+	for {
+		f, err := src.Next()
+		if err == io.EOF {
+			return features, src
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		features = append(features, f)
+	}
+}
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestGeoJSON(t *testing.T) {
+	// crs after features, numeric code and an unknown member are all handled while streaming
+	path := writeFile(t, "kabupaten.geojson", []byte(`{
+		"type": "FeatureCollection",
+		"name": "kabupaten",
+		"features": [
+			{"type": "Feature", "properties": {"KODE": 3201, "NAMA": " Kabupaten Bogor "}, "geometry": {"type": "Polygon", "coordinates": [[[0,0],[1,0],[1,1],[0,0]]]}},
+			{"type": "Feature", "properties": {"KODE": "3202", "NAMA": "Kabupaten Sukabumi"}, "geometry": {"type": "Polygon", "coordinates": [[[0,0],[1,0],[1,1],[0,0]]]}}
+		],
+		"crs": {"type": "name", "properties": {"name": "urn:ogc:def:crs:EPSG::32748"}}
+	}`))
+
+	features, src := readAll(t, path, importer.FormatGeoJSON)
+	if len(features) != 2 {
+		t.Fatalf("got %d features, want 2", len(features))
+	}
+	if got := features[0].Properties["KODE"]; got != json.Number("3201") {
+		t.Errorf("KODE = %#v, want json.Number 3201", got)
+	}
+	if src.SRID() != 32748 || src.Rings() {
+		t.Errorf("SRID = %d, Rings = %v, want 32748 and false", src.SRID(), src.Rings())
+	}
+}
+
+func TestGeoJSONInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"not a collection", `{"type": "Feature"}`, "want FeatureCollection"},
+		{"null geometry", `{"type": "FeatureCollection", "features": [{"type": "Feature", "properties": {}, "geometry": null}]}`, "geometry is null"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := importer.Open(writeFile(t, "x.geojson", []byte(tt.data)), importer.FormatGeoJSON)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer src.Close()
+
+			_, err = src.Next()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeoJSONSeq(t *testing.T) {
+	feature := `{"type": "Feature", "properties": {"KODE": "32"}, "geometry": {"type": "Point", "coordinates": [0, 0]}}`
+	path := writeFile(t, "propinsi.geojsonl", []byte("\x1e"+feature+"\n\n"+feature+"\n"))
+
+	features, src := readAll(t, path, importer.FormatGeoJSONSeq)
+	if len(features) != 2 || src.SRID() != 0 {
+		t.Errorf("got %d features with SRID %d, want 2 with SRID 0", len(features), src.SRID())
+	}
+}
+
+func TestShapefile(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "kecamatan")
+
+	outer := [][2]float64{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}
+	hole := [][2]float64{{2, 2}, {4, 2}, {4, 4}, {2, 4}, {2, 2}}
+	writeShp(t, base+".shp", [][][][2]float64{{outer, hole}, {outer}, {outer}})
+	writeDbf(t, base+".dbf", []string{"KDCPUM", "WADMKC"}, [][]string{
+		{"3201010", "Nanggung"},
+		{"3201099", "Dihapus"},
+		{"3201020", "Leuwiliang\xe9"},
+	}, 1)
+	if err := os.WriteFile(base+".prj", []byte(`PROJCS["WGS 84 / UTM zone 48S",GEOGCS["WGS 84",AUTHORITY["EPSG","4326"]],AUTHORITY["EPSG","32748"]]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	format, err := importer.DetectFormat(base + ".shp")
+	if err != nil || format != importer.FormatShapefile {
+		t.Fatalf("DetectFormat = %q, %v", format, err)
+	}
+
+	features, src := readAll(t, base+".shp", format)
+	if len(features) != 2 {
+		t.Fatalf("got %d features, want 2 (deleted record skipped)", len(features))
+	}
+	if features[0].Properties["KDCPUM"] != "3201010" || features[1].Properties["WADMKC"] != "Leuwiliangé" {
+		t.Errorf("properties = %v, %v", features[0].Properties, features[1].Properties)
+	}
+	if src.SRID() != 32748 || !src.Rings() {
+		t.Errorf("SRID = %d, Rings = %v, want 32748 and true", src.SRID(), src.Rings())
+	}
+
+	var geometry struct {
+		Type        string
+		Coordinates [][][2]float64
+	}
+	if err := json.Unmarshal(features[0].Geometry, &geometry); err != nil {
+		t.Fatal(err)
+	}
+	if geometry.Type != "MultiLineString" || len(geometry.Coordinates) != 2 || geometry.Coordinates[1][1] != hole[1] {
+		t.Errorf("geometry = %+v, want both rings as MultiLineString", geometry)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		cfg  importer.Config
+		want string
+	}{
+		{importer.Config{Level: "propinsi", CodeField: "KODE", NameField: "NAMA"}, ""},
+		{importer.Config{Level: "desa", CodeField: "KODE", NameField: "NAMA"}, "unknown level"},
+		{importer.Config{Level: "kabupaten", CodeField: "KODE", NameField: "NAMA"}, "parent_field is required"},
+		{importer.Config{Level: "kelurahan", NameField: "NAMA", ParentField: "KDCPUM"}, "code_field and name_field"},
+	}
+
+	for _, tt := range tests {
+		err := tt.cfg.Validate()
+		if (tt.want == "" && err != nil) || (tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want))) {
+			t.Errorf("%+v: err = %v, want %q", tt.cfg, err, tt.want)
+		}
+	}
+}
+
+// writeShp menulis shapefile polygon minimal; setiap shape adalah daftar ring
+func writeShp(t *testing.T, path string, shapes [][][][2]float64) {
+	t.Helper()
+
+	var records bytes.Buffer
+	for i, rings := range shapes {
+		var content bytes.Buffer
+		le := func(v interface{}) { binary.Write(&content, binary.LittleEndian, v) }
+
+		points := 0
+		for _, r := range rings {
+			points += len(r)
+		}
+		le(int32(5))
+		le([4]float64{})
+		le(int32(len(rings)))
+		le(int32(points))
+		start := 0
+		for _, r := range rings {
+			le(int32(start))
+			start += len(r)
+		}
+		for _, r := range rings {
+			for _, p := range r {
+				le(math.Float64bits(p[0]))
+				le(math.Float64bits(p[1]))
+			}
+		}
+
+		binary.Write(&records, binary.BigEndian, int32(i+1))
+		binary.Write(&records, binary.BigEndian, int32(content.Len()/2))
+		records.Write(content.Bytes())
+	}
+
+	header := make([]byte, 100)
+	binary.BigEndian.PutUint32(header[0:], 9994)
+	binary.BigEndian.PutUint32(header[24:], uint32((100+records.Len())/2))
+	binary.LittleEndian.PutUint32(header[28:], 1000)
+	binary.LittleEndian.PutUint32(header[32:], 5)
+
+	if err := os.WriteFile(path, append(header, records.Bytes()...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeDbf menulis file dBase III dengan kolom karakter lebar 20; record deleted diberi tanda hapus
+func writeDbf(t *testing.T, path string, fields []string, rows [][]string, deleted int) {
+	t.Helper()
+	const width = 20
+
+	var b bytes.Buffer
+	header := make([]byte, 32)
+	header[0] = 3
+	binary.LittleEndian.PutUint32(header[4:], uint32(len(rows)))
+	binary.LittleEndian.PutUint16(header[8:], uint16(32+32*len(fields)+1))
+	binary.LittleEndian.PutUint16(header[10:], uint16(1+width*len(fields)))
+	b.Write(header)
+
+	for _, f := range fields {
+		desc := make([]byte, 32)
+		copy(desc, f)
+		desc[11] = 'C'
+		desc[16] = width
+		b.Write(desc)
+	}
+	b.WriteByte(0x0D)
+
+	for i, row := range rows {
+		if i == deleted {
+			b.WriteByte('*')
+		} else {
+			b.WriteByte(' ')
+		}
+		for _, v := range row {
+			b.WriteString(v + strings.Repeat(" ", width-len(v)))
+		}
+	}
+	b.WriteByte(0x1A)
+
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
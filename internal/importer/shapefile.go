@@ -0,0 +1,255 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Tipe shape polygon yang didukung; varian Z dan M hanya dibaca koordinat X/Y-nya
+const (
+	shapeNull     = 0
+	shapePolygon  = 5
+	shapePolygonZ = 15
+	shapePolygonM = 25
+)
+
+// prjAuthority mencocokkan AUTHORITY["EPSG","32748"] terakhir (milik CRS terluar) di file .prj
+var prjAuthority = regexp.MustCompile(`AUTHORITY\["EPSG",\s*"(\d+)"\]\]\s*$`)
+
+// shapefileSource membaca .shp dan .dbf secara berpasangan. Ring polygon dikirim sebagai
+// MultiLineString karena shapefile tidak mengelompokkan hole ke ring luarnya secara eksplisit;
+// perakitan polygon dilakukan PostGIS (ST_BuildArea).
+type shapefileSource struct {
+	shp    *os.File
+	dbf    *os.File
+	shpR   *bufio.Reader
+	dbfR   *bufio.Reader
+	fields []dbfField
+	rowLen int
+	rows   int
+	n      int
+	srid   int
+}
+
+// dbfField adalah deskriptor kolom dBase
+type dbfField struct {
+	name   string
+	length int
+}
+
+func openShapefile(path string) (*shapefileSource, error) {
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+
+	shp, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	dbf, err := os.Open(base + ".dbf")
+	if err != nil {
+		shp.Close()
+		return nil, fmt.Errorf("shapefile attributes: %w", err)
+	}
+
+	s := &shapefileSource{shp: shp, dbf: dbf, shpR: bufio.NewReaderSize(shp, 1<<20), dbfR: bufio.NewReaderSize(dbf, 1<<16)}
+	if err := s.readHeaders(); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	if prj, err := os.ReadFile(base + ".prj"); err == nil {
+		if m := prjAuthority.FindSubmatch(prj); m != nil {
+			s.srid, _ = strconv.Atoi(string(m[1]))
+		}
+	}
+
+	return s, nil
+}
+
+func (s *shapefileSource) readHeaders() error {
+	var header [100]byte
+	if _, err := io.ReadFull(s.shpR, header[:]); err != nil {
+		return fmt.Errorf("shapefile header: %w", err)
+	}
+	if binary.BigEndian.Uint32(header[0:4]) != 9994 {
+		return errors.New("not a shapefile: bad file code")
+	}
+	switch t := binary.LittleEndian.Uint32(header[32:36]); t {
+	case shapePolygon, shapePolygonZ, shapePolygonM:
+	default:
+		return fmt.Errorf("unsupported shape type %d, only polygon shapefiles can be imported", t)
+	}
+
+	var dbfHeader [32]byte
+	if _, err := io.ReadFull(s.dbfR, dbfHeader[:]); err != nil {
+		return fmt.Errorf("dbf header: %w", err)
+	}
+	s.rows = int(binary.LittleEndian.Uint32(dbfHeader[4:8]))
+	headerLen := int(binary.LittleEndian.Uint16(dbfHeader[8:10]))
+	s.rowLen = int(binary.LittleEndian.Uint16(dbfHeader[10:12]))
+
+	read := len(dbfHeader)
+	for {
+		first, err := s.dbfR.ReadByte()
+		if err != nil {
+			return fmt.Errorf("dbf fields: %w", err)
+		}
+		read++
+		if first == 0x0D {
+			break
+		}
+
+		var desc [32]byte
+		desc[0] = first
+		if _, err := io.ReadFull(s.dbfR, desc[1:]); err != nil {
+			return fmt.Errorf("dbf fields: %w", err)
+		}
+		read += len(desc) - 1
+
+		name := string(desc[:11])
+		if i := strings.IndexByte(name, 0); i >= 0 {
+			name = name[:i]
+		}
+		s.fields = append(s.fields, dbfField{name: name, length: int(desc[16])})
+	}
+
+	// Lewati sisa header (mis. backlink Visual FoxPro) sampai record pertama
+	if _, err := s.dbfR.Discard(headerLen - read); err != nil {
+		return fmt.Errorf("dbf header: %w", err)
+	}
+	return nil
+}
+
+func (s *shapefileSource) Next() (*Feature, error) {
+	for {
+		if s.n == s.rows {
+			return nil, io.EOF
+		}
+		s.n++
+
+		geometry, err := s.readShape()
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", s.n, err)
+		}
+		properties, deleted, err := s.readRow()
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", s.n, err)
+		}
+		if deleted {
+			continue
+		}
+		if geometry == nil {
+			return nil, fmt.Errorf("record %d: geometry is null", s.n)
+		}
+
+		return &Feature{Properties: properties, Geometry: geometry}, nil
+	}
+}
+
+// readShape membaca satu record .shp dan mengembalikan ring-nya sebagai GeoJSON MultiLineString
+func (s *shapefileSource) readShape() (json.RawMessage, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(s.shpR, header[:]); err != nil {
+		return nil, err
+	}
+	content := make([]byte, 2*int(binary.BigEndian.Uint32(header[4:8])))
+	if _, err := io.ReadFull(s.shpR, content); err != nil {
+		return nil, err
+	}
+
+	if len(content) < 4 {
+		return nil, errors.New("truncated shape")
+	}
+	shapeType := binary.LittleEndian.Uint32(content[0:4])
+	switch shapeType {
+	case shapeNull:
+		return nil, nil
+	case shapePolygon, shapePolygonZ, shapePolygonM:
+	default:
+		return nil, fmt.Errorf("unsupported shape type %d", shapeType)
+	}
+	if len(content) < 44 {
+		return nil, errors.New("truncated polygon")
+	}
+
+	numParts := int(binary.LittleEndian.Uint32(content[36:40]))
+	numPoints := int(binary.LittleEndian.Uint32(content[40:44]))
+	pointsAt := 44 + 4*numParts
+	if len(content) < pointsAt+16*numPoints {
+		return nil, errors.New("truncated polygon")
+	}
+
+	parts := make([]int, numParts+1)
+	for i := 0; i < numParts; i++ {
+		parts[i] = int(binary.LittleEndian.Uint32(content[44+4*i:]))
+	}
+	parts[numParts] = numPoints
+
+	rings := make([][][2]float64, 0, numParts)
+	for i := 0; i < numParts; i++ {
+		if parts[i] > parts[i+1] || parts[i+1] > numPoints {
+			return nil, errors.New("invalid part index")
+		}
+		ring := make([][2]float64, 0, parts[i+1]-parts[i])
+		for p := parts[i]; p < parts[i+1]; p++ {
+			at := pointsAt + 16*p
+			ring = append(ring, [2]float64{
+				math.Float64frombits(binary.LittleEndian.Uint64(content[at:])),
+				math.Float64frombits(binary.LittleEndian.Uint64(content[at+8:])),
+			})
+		}
+		rings = append(rings, ring)
+	}
+
+	return json.Marshal(map[string]interface{}{"type": "MultiLineString", "coordinates": rings})
+}
+
+// readRow membaca satu record .dbf; deleted bernilai true untuk record bertanda hapus
+func (s *shapefileSource) readRow() (map[string]interface{}, bool, error) {
+	row := make([]byte, s.rowLen)
+	if _, err := io.ReadFull(s.dbfR, row); err != nil {
+		return nil, false, err
+	}
+
+	properties := make(map[string]interface{}, len(s.fields))
+	at := 1
+	for _, f := range s.fields {
+		if at+f.length > len(row) {
+			return nil, false, errors.New("dbf record shorter than its fields")
+		}
+		properties[f.name] = dbfString(row[at : at+f.length])
+		at += f.length
+	}
+
+	return properties, row[0] == '*', nil
+}
+
+// dbfString memangkas spasi dan padding NUL nilai dbf; nilai non-UTF-8 dianggap Latin-1
+func dbfString(b []byte) string {
+	if utf8.Valid(b) {
+		return strings.Trim(string(b), " \x00")
+	}
+
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return strings.Trim(string(runes), " \x00")
+}
+
+func (s *shapefileSource) SRID() int   { return s.srid }
+func (s *shapefileSource) Rings() bool { return true }
+
+func (s *shapefileSource) Close() error {
+	return errors.Join(s.shp.Close(), s.dbf.Close())
+}
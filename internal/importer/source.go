@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Format sumber yang didukung
+const (
+	FormatGeoJSON    = "geojson"
+	FormatGeoJSONSeq = "geojsonseq"
+	FormatShapefile  = "shapefile"
+)
+
+// Feature adalah satu record sumber: atribut dan geometri dalam bentuk GeoJSON
+type Feature struct {
+	Properties map[string]interface{}
+	Geometry   json.RawMessage
+}
+
+// Source membaca feature satu per satu; Next mengembalikan io.EOF setelah feature terakhir
+type Source interface {
+	Next() (*Feature, error)
+	// SRID mengembalikan SRID yang dideklarasikan sumber (0 jika tidak ada);
+	// untuk GeoJSON nilainya baru pasti setelah Next mengembalikan io.EOF
+	SRID() int
+	// Rings bernilai true jika geometri berupa MultiLineString ring yang harus dirakit menjadi polygon
+	Rings() bool
+	Close() error
+}
+
+// DetectFormat menebak format dari ekstensi file
+func DetectFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".geojson", ".json":
+		return FormatGeoJSON, nil
+	case ".geojsons", ".geojsonl", ".geojsonseq", ".ndjson", ".jsonl":
+		return FormatGeoJSONSeq, nil
+	case ".shp":
+		return FormatShapefile, nil
+	}
+	return "", fmt.Errorf("cannot detect format of %s, use -format", path)
+}
+
+// Open membuka file sumber dengan format tertentu
+func Open(path, format string) (Source, error) {
+	if format == FormatShapefile {
+		return openShapefile(path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatGeoJSON:
+		return newGeoJSONSource(f), nil
+	case FormatGeoJSONSeq:
+		return newGeoJSONSeqSource(f), nil
+	}
+
+	f.Close()
+	return nil, fmt.Errorf("unknown format %q, must be geojson, geojsonseq or shapefile", format)
+}
+
+// epsgCode mencocokkan kode EPSG di nama CRS GeoJSON ("EPSG:32748", "urn:ogc:def:crs:EPSG::32748")
+var epsgCode = regexp.MustCompile(`EPSG:+(\d+)$`)
+
+// parseCRSName mengubah nama CRS menjadi SRID; CRS84 setara dengan EPSG:4326
+func parseCRSName(name string) int {
+	if strings.HasSuffix(name, "CRS84") {
+		return 4326
+	}
+	if m := epsgCode.FindStringSubmatch(name); m != nil {
+		srid, _ := strconv.Atoi(m[1])
+		return srid
+	}
+	return 0
+}
+
+// propertyString mengubah nilai atribut menjadi string tanpa spasi di tepi
+func propertyString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
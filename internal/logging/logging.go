@@ -0,0 +1,86 @@
+// Package logging menyediakan structured logger (log/slog) dengan format sesuai environment,
+// serta logger per request yang dibawa lewat context.Context.
+package logging
+
+import (
+	"context"
+	"io"
+	"location-svc/internal/apperror"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// New membuat logger dengan level minimum level. Development memakai format teks yang
+// mudah dibaca, selain itu JSON satu baris per log.
+func New(w io.Writer, level slog.Level, development bool) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	if development {
+		return slog.New(slog.NewTextHandler(w, opts))
+	}
+	return slog.New(slog.NewJSONHandler(w, opts))
+}
+
+type contextKey struct{}
+
+// NewContext menyimpan logger di ctx
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext mengambil logger request dari ctx, atau slog.Default jika tidak ada
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// FromEcho mengambil logger request dari echo.Context
+func FromEcho(c echo.Context) *slog.Logger {
+	return FromContext(c.Request().Context())
+}
+
+// Middleware membuat logger per request dengan request_id (dari middleware.RequestID, yang harus
+// dipasang lebih dulu), menyimpannya di context request, dan mencatat satu log per request.
+// Error handler dipanggil di sini agar status code akhir ikut tercatat.
+func Middleware(logger *slog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			req := c.Request()
+			res := c.Response()
+
+			reqLogger := logger.With("request_id", res.Header().Get(echo.HeaderXRequestID))
+			c.SetRequest(req.WithContext(NewContext(req.Context(), reqLogger)))
+
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			level := slog.LevelInfo
+			switch {
+			case res.Status >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case res.Status == apperror.StatusClientClosedRequest:
+				// Client memutus koneksi, bukan kesalahan client maupun server
+			case res.Status >= http.StatusBadRequest:
+				level = slog.LevelWarn
+			}
+
+			reqLogger.LogAttrs(req.Context(), level, "request",
+				slog.String("method", req.Method),
+				slog.String("uri", req.RequestURI),
+				slog.String("route", c.Path()),
+				slog.Int("status", res.Status),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+				slog.Int64("bytes_out", res.Size),
+				slog.String("remote_ip", c.RealIP()),
+				slog.String("user_agent", req.UserAgent()),
+			)
+			return nil
+		}
+	}
+}
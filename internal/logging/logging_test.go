@@ -0,0 +1,100 @@
+package logging_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"location-svc/internal/logging"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func TestNewFiltersByLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, slog.LevelWarn, false)
+
+	logger.Info("dropped")
+	logger.Warn("kept", "key", "value")
+
+	lines := decodeLines(t, &buf)
+	if len(lines) != 1 {
+		t.Fatalf("got %d log lines, want 1: %v", len(lines), lines)
+	}
+	if lines[0]["msg"] != "kept" || lines[0]["key"] != "value" {
+		t.Errorf("unexpected log line %v", lines[0])
+	}
+}
+
+func TestFromContextDefault(t *testing.T) {
+	if got := logging.FromContext(context.Background()); got != slog.Default() {
+		t.Error("FromContext without logger should return slog.Default")
+	}
+
+	logger := slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil))
+	if got := logging.FromContext(logging.NewContext(context.Background(), logger)); got != logger {
+		t.Error("FromContext should return the logger stored by NewContext")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, slog.LevelInfo, false)
+
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	e.Use(logging.Middleware(logger))
+	e.GET("/items/:id", func(c echo.Context) error {
+		logging.FromEcho(c).Info("handler")
+		return echo.ErrNotFound
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/items/42", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+	requestID := rec.Header().Get(echo.HeaderXRequestID)
+	if requestID == "" {
+		t.Fatal("missing X-Request-Id header")
+	}
+
+	lines := decodeLines(t, &buf)
+	if len(lines) != 2 {
+		t.Fatalf("got %d log lines, want 2: %v", len(lines), lines)
+	}
+	for _, line := range lines {
+		if line["request_id"] != requestID {
+			t.Errorf("request_id = %v, want %q in %v", line["request_id"], requestID, line)
+		}
+	}
+
+	access := lines[1]
+	if access["msg"] != "request" || access["level"] != "WARN" {
+		t.Errorf("unexpected access log %v", access)
+	}
+	if access["route"] != "/items/:id" || access["status"] != float64(http.StatusNotFound) {
+		t.Errorf("access log route/status = %v/%v", access["route"], access["status"])
+	}
+}
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("invalid JSON log line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,188 @@
+// Package metrics mengumpulkan metrics Prometheus untuk HTTP, database dan cache,
+// dan menyajikannya di endpoint /metrics.
+package metrics
+
+import (
+	"database/sql"
+	"location-svc/internal/apperror"
+	"location-svc/internal/cache"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace adalah prefix semua metrics service
+const namespace = "location"
+
+// Metrics menyimpan registry dan collector service
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests      *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	geojsonSize   *prometheus.HistogramVec
+	queryDuration *prometheus.HistogramVec
+	queryErrors   *prometheus.CounterVec
+}
+
+// New membuat Metrics dengan registry baru yang sudah berisi metrics runtime Go dan proses
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method and route template.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		geojsonSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "geojson_response_size_bytes",
+			Help:      "Uncompressed response payload size of /geojson endpoints by route template.",
+			Buckets:   prometheus.ExponentialBuckets(1<<10, 4, 9), // 1 KiB - 64 MiB
+		}, []string{"route"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_query_duration_seconds",
+			Help:      "Duration of repository calls by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		queryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "repository_query_errors_total",
+			Help:      "Failed repository calls by method and error code.",
+		}, []string{"method", "code"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests, m.duration, m.geojsonSize, m.queryDuration, m.queryErrors,
+	)
+	return m
+}
+
+// RegisterDB menambahkan statistik pool database/sql (koneksi terbuka, idle, wait, dll)
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// RegisterCache menambahkan counter cache dengan nama tertentu, dibaca dari stats saat scrape
+func (m *Metrics) RegisterCache(name string, stats func() cache.Stats) {
+	m.registry.MustRegister(newCacheCollector(name, stats))
+}
+
+// ObserveQuery mencatat durasi dan error satu pemanggilan repository; cocok sebagai
+// repositories.QueryObserver. NotFound, InvalidArgument dan Canceled (client memutus koneksi)
+// bukan kegagalan query sehingga tidak dihitung.
+func (m *Metrics) ObserveQuery(method string, duration time.Duration, err error) {
+	m.queryDuration.WithLabelValues(method).Observe(duration.Seconds())
+	if err == nil {
+		return
+	}
+
+	code := apperror.CodeInternal
+	if appErr, ok := apperror.As(err); ok {
+		code = appErr.Code
+	}
+	if code != apperror.CodeNotFound && code != apperror.CodeInvalidArgument && code != apperror.CodeCanceled {
+		m.queryErrors.WithLabelValues(method, strings.ToLower(string(code))).Inc()
+	}
+}
+
+// payloadSizeKey adalah key echo.Context untuk ukuran payload yang dicatat SetPayloadSize
+const payloadSizeKey = "metrics.payload_size"
+
+// SetPayloadSize mencatat ukuran payload sebelum kompresi untuk geojson_response_size_bytes.
+// Dipakai handler yang mengirim body yang sudah dikompresi (mis. dari cache); selain itu
+// ukuran diambil dari byte yang ditulis handler, sebelum middleware kompresi.
+func SetPayloadSize(c echo.Context, n int) {
+	c.Set(payloadSizeKey, n)
+}
+
+// Middleware mencatat jumlah request, latency dan ukuran payload GeoJSON per route template.
+// Error handler dipanggil di sini agar status code akhir ikut tercatat.
+func (m *Metrics) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+			method := c.Request().Method
+			res := c.Response()
+
+			m.requests.WithLabelValues(method, route, strconv.Itoa(res.Status)).Inc()
+			m.duration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+			if strings.HasPrefix(route, "/geojson/") && res.Status == http.StatusOK {
+				size := res.Size
+				if n, ok := c.Get(payloadSizeKey).(int); ok {
+					size = int64(n)
+				}
+				m.geojsonSize.WithLabelValues(route).Observe(float64(size))
+			}
+			return nil
+		}
+	}
+}
+
+// Handler menyajikan metrics dalam format exposition Prometheus
+func (m *Metrics) Handler() echo.HandlerFunc {
+	return echo.WrapHandler(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+}
+
+// cacheCollector mengekspos cache.Stats sebagai metrics Prometheus
+type cacheCollector struct {
+	stats                                              func() cache.Stats
+	hits, misses, coalesced, evictions, entries, bytes *prometheus.Desc
+}
+
+func newCacheCollector(name string, stats func() cache.Stats) *cacheCollector {
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", metric), help, nil, prometheus.Labels{"cache": name})
+	}
+	return &cacheCollector{
+		stats:     stats,
+		hits:      desc("hits_total", "Cache lookups served from memory."),
+		misses:    desc("misses_total", "Cache lookups that loaded from the store."),
+		coalesced: desc("coalesced_total", "Cache misses that waited for a concurrent load of the same key."),
+		evictions: desc("evictions_total", "Entries evicted because the cache was full or over its byte budget."),
+		entries:   desc("entries", "Entries currently in the cache."),
+		bytes:     desc("bytes", "Estimated memory held by cached values."),
+	}
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.coalesced
+	ch <- c.evictions
+	ch <- c.entries
+	ch <- c.bytes
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.stats()
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(s.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(s.Misses))
+	ch <- prometheus.MustNewConstMetric(c.coalesced, prometheus.CounterValue, float64(s.Coalesced))
+	ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(s.Evictions))
+	ch <- prometheus.MustNewConstMetric(c.entries, prometheus.GaugeValue, float64(s.Entries))
+	ch <- prometheus.MustNewConstMetric(c.bytes, prometheus.GaugeValue, float64(s.Bytes))
+}
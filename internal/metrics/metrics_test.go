@@ -0,0 +1,67 @@
+package metrics_test
+
+import (
+	"errors"
+	"location-svc/internal/apperror"
+	"location-svc/internal/cache"
+	"location-svc/internal/metrics"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestMetrics(t *testing.T) {
+	m := metrics.New()
+	m.RegisterCache("geojson", func() cache.Stats { return cache.Stats{Hits: 7, Misses: 2, Entries: 2} })
+	m.ObserveQuery("GetPropinsiGeoJSON", 20*time.Millisecond, nil)
+	m.ObserveQuery("GetPropinsiGeoJSON", time.Millisecond, apperror.NotFound("Province not found"))
+	m.ObserveQuery("Search", time.Second, apperror.Wrap(apperror.CodeTimeout, "Database query timed out", errors.New("deadline exceeded")))
+	m.ObserveQuery("Search", time.Second, apperror.Wrap(apperror.CodeCanceled, "Request canceled by client", errors.New("canceled")))
+
+	e := echo.New()
+	e.Use(m.Middleware())
+	e.GET("/geojson/propinsi/:id", func(c echo.Context) error {
+		switch c.Param("id") {
+		case "99":
+			return echo.ErrNotFound
+		case "31":
+			// Body sudah dikompresi handler; yang dicatat ukuran sebelum kompresi
+			metrics.SetPayloadSize(c, 8192)
+			c.Response().Header().Set(echo.HeaderContentEncoding, "gzip")
+			return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, make([]byte, 64))
+		}
+		return c.String(http.StatusOK, strings.Repeat("x", 2048))
+	})
+	e.GET("/metrics", m.Handler())
+
+	for _, target := range []string{"/geojson/propinsi/32", "/geojson/propinsi/31", "/geojson/propinsi/99", "/nope"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		`location_http_requests_total{method="GET",route="/geojson/propinsi/:id",status="200"} 2`,
+		`location_http_requests_total{method="GET",route="/geojson/propinsi/:id",status="404"} 1`,
+		`location_http_request_duration_seconds_count{method="GET",route="/geojson/propinsi/:id"} 3`,
+		`location_geojson_response_size_bytes_sum{route="/geojson/propinsi/:id"} 10240`,
+		`location_repository_query_duration_seconds_count{method="GetPropinsiGeoJSON"} 2`,
+		`location_repository_query_errors_total{code="timeout",method="Search"} 1`,
+		`location_cache_hits_total{cache="geojson"} 7`,
+		`location_cache_entries{cache="geojson"} 2`,
+		"go_goroutines",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output is missing %q", want)
+		}
+	}
+	if strings.Contains(body, `code="not_found"`) || strings.Contains(body, `code="canceled"`) {
+		t.Error("NotFound or Canceled counted as a query error")
+	}
+}
@@ -0,0 +1,279 @@
+// Package migrate menjalankan migrasi schema database yang versioned dan di-embed ke dalam binary
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"location-svc/database"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// lockID adalah key pg_advisory_lock agar hanya satu proses yang menjalankan migrasi
+const lockID = 7_318_012
+
+// fileName adalah format nama file migrasi: NNNN_nama.up.sql atau NNNN_nama.down.sql
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration adalah satu langkah migrasi beserta SQL up dan down
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status adalah sebuah migrasi beserta waktu diterapkan; AppliedAt nil jika belum diterapkan
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Load membaca dan memvalidasi migrasi dari fsys, terurut berdasarkan versi.
+// Setiap versi harus unik, berurutan mulai dari 1, dan memiliki file up dan down.
+func Load(fsys fs.FS) ([]Migration, error) {
+	paths, err := fs.Glob(fsys, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, path := range paths {
+		name := path[len("migrations/"):]
+		m := fileName.FindStringSubmatch(name)
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", name)
+		}
+
+		version, _ := strconv.Atoi(m[1])
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, migration.Name, m[2])
+		}
+
+		body, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return nil, err
+		}
+		if m[3] == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration versions must be sequential from 1, found %d at position %d", m.Version, i+1)
+		}
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both up and down files", m.Version, m.Name)
+		}
+	}
+
+	return migrations, nil
+}
+
+// Migrator menerapkan migrasi dan mencatatnya di tabel schema_migrations
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New creates new instance of Migrator dengan migrasi yang di-embed di package database
+func New(db *sql.DB) (*Migrator, error) {
+	migrations, err := Load(database.Migrations)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Latest mengembalikan versi migrasi terbaru yang dikenal binary ini
+func (m *Migrator) Latest() int {
+	return len(m.migrations)
+}
+
+// Version mengembalikan versi migrasi tertinggi yang sudah diterapkan, 0 jika belum ada
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	applied, err := m.applied(ctx, m.db)
+	if err != nil {
+		return 0, err
+	}
+
+	version := 0
+	for v := range applied {
+		version = max(version, v)
+	}
+	return version, nil
+}
+
+// Status mengembalikan semua migrasi beserta status penerapannya
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied(context.Background(), m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = Status{Migration: migration}
+		if at, ok := applied[migration.Version]; ok {
+			statuses[i].AppliedAt = &at
+		}
+	}
+	return statuses, nil
+}
+
+// Up menerapkan semua migrasi yang belum diterapkan, masing-masing dalam transaksi sendiri,
+// dan mengembalikan migrasi yang baru diterapkan
+func (m *Migrator) Up() ([]Migration, error) {
+	var done []Migration
+
+	err := m.locked(func(conn *sql.Conn) error {
+		applied, err := m.applied(context.Background(), conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			err := m.apply(conn, migration.Up,
+				"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name)
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s up: %w", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// Down membatalkan steps migrasi terakhir yang sudah diterapkan dan mengembalikan migrasi yang dibatalkan
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	var done []Migration
+
+	err := m.locked(func(conn *sql.Conn) error {
+		applied, err := m.applied(context.Background(), conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			err := m.apply(conn, migration.Down,
+				"DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s down: %w", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// querier adalah subset *sql.DB dan *sql.Conn yang dipakai applied
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// applied mengembalikan versi yang sudah diterapkan beserta waktunya.
+// Database yang belum pernah dimigrasi (tanpa schema_migrations) dianggap kosong.
+func (m *Migrator) applied(ctx context.Context, q querier) (map[int]time.Time, error) {
+	applied := make(map[int]time.Time)
+
+	var exists bool
+	err := q.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists)
+	if err != nil || !exists {
+		return applied, err
+	}
+
+	rows, err := q.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
+
+// locked menjalankan fn pada satu koneksi yang memegang advisory lock migrasi,
+// setelah memastikan tabel schema_migrations ada
+func (m *Migrator) locked(fn func(conn *sql.Conn) error) error {
+	ctx := context.Background()
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", lockID)
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+// apply menjalankan body migrasi dan perubahan catatan schema_migrations dalam satu transaksi
+func (m *Migrator) apply(conn *sql.Conn, body, record string, args ...interface{}) error {
+	ctx := context.Background()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, body); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
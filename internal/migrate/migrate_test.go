@@ -0,0 +1,73 @@
+package migrate_test
+
+import (
+	"location-svc/database"
+	"location-svc/internal/migrate"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadEmbedded(t *testing.T) {
+	migrations, err := migrate.Load(database.Migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+
+	for i, m := range migrations {
+		if m.Version != i+1 || m.Up == "" || m.Down == "" {
+			t.Errorf("migration %d = %04d_%s, want sequential version with up and down", i, m.Version, m.Name)
+		}
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	file := func(body string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(body)} }
+
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{
+			"bad file name",
+			fstest.MapFS{"migrations/create.sql": file("SELECT 1")},
+			"invalid migration file name",
+		},
+		{
+			"missing down",
+			fstest.MapFS{"migrations/0001_init.up.sql": file("SELECT 1")},
+			"needs both up and down",
+		},
+		{
+			"gap in versions",
+			fstest.MapFS{
+				"migrations/0001_init.up.sql":   file("SELECT 1"),
+				"migrations/0001_init.down.sql": file("SELECT 1"),
+				"migrations/0003_next.up.sql":   file("SELECT 1"),
+				"migrations/0003_next.down.sql": file("SELECT 1"),
+			},
+			"sequential",
+		},
+		{
+			"conflicting names",
+			fstest.MapFS{
+				"migrations/0001_init.up.sql":    file("SELECT 1"),
+				"migrations/0001_other.down.sql": file("SELECT 1"),
+			},
+			"conflicting names",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := migrate.Load(tt.fsys)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
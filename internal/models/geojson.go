@@ -0,0 +1,23 @@
+package models
+
+import "encoding/json"
+
+// AppendJSON menambahkan JSON Feature ke buf. Hanya properties yang di-encode;
+// geometry dari ST_AsGeoJSON sudah berupa JSON compact sehingga disalin apa adanya.
+// Hasilnya sama dengan json.Marshal(f) tanpa memindai ulang geometry yang bisa berukuran megabyte.
+func (f *GeoJSONFeature) AppendJSON(buf []byte) ([]byte, error) {
+	props, err := json.Marshal(f.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	buf = append(buf, `{"type":"Feature","properties":`...)
+	buf = append(buf, props...)
+	buf = append(buf, `,"geometry":`...)
+	if len(f.Geometry) == 0 {
+		buf = append(buf, "null"...)
+	} else {
+		buf = append(buf, f.Geometry...)
+	}
+	return append(buf, '}'), nil
+}
@@ -0,0 +1,29 @@
+package models_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"location-svc/internal/models"
+	"testing"
+)
+
+func TestGeoJSONFeatureAppendJSON(t *testing.T) {
+	features := []*models.GeoJSONFeature{
+		{
+			Type:       "Feature",
+			Properties: models.GeoJSONProperties{ID: 3201, Name: "Kabupaten \"Bogor\" <Barat>", Type: "kabupaten", KdPropinsi: "32"},
+			Geometry:   json.RawMessage(`{"type":"Polygon","coordinates":[[[106.7,-6.5],[106.9,-6.5],[106.9,-6.7],[106.7,-6.5]]]}`),
+		},
+		{Type: "Feature"},
+	}
+	for _, f := range features {
+		got, err := f.AppendJSON([]byte("prefix"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := json.Marshal(f)
+		if !bytes.Equal(got, append([]byte("prefix"), want...)) {
+			t.Errorf("AppendJSON differs from json.Marshal:\n%s\nprefix%s", got, want)
+		}
+	}
+}
@@ -1,51 +1,172 @@
 package models
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // Propinsi represents provinsi data
 type Propinsi struct {
-	KdPropinsi string `json:"kd_propinsi"`
-	NmPropinsi string `json:"nm_propinsi"`
+	KdPropinsi string  `json:"kd_propinsi"`
+	NmPropinsi string  `json:"nm_propinsi"`
+	Score      float64 `json:"score,omitempty"`
 }
 
 // Kabupaten represents kabupaten data with provinsi info
 type Kabupaten struct {
-	KdPropinsi  string `json:"kd_propinsi"`
-	NmPropinsi  string `json:"nm_propinsi"`
-	KdKabupaten string `json:"kd_kabupaten"`
-	NmKabupaten string `json:"nm_kabupaten"`
+	KdPropinsi  string  `json:"kd_propinsi"`
+	NmPropinsi  string  `json:"nm_propinsi"`
+	KdKabupaten string  `json:"kd_kabupaten"`
+	NmKabupaten string  `json:"nm_kabupaten"`
+	Score       float64 `json:"score,omitempty"`
 }
 
 // Kecamatan represents kecamatan data with full hierarchy
 type Kecamatan struct {
-	KdPropinsi  string `json:"kd_propinsi"`
-	NmPropinsi  string `json:"nm_propinsi"`
-	KdKabupaten string `json:"kd_kabupaten"`
-	NmKabupaten string `json:"nm_kabupaten"`
-	KdKecamatan string `json:"kd_kecamatan"`
-	NmKecamatan string `json:"nm_kecamatan"`
+	KdPropinsi  string  `json:"kd_propinsi"`
+	NmPropinsi  string  `json:"nm_propinsi"`
+	KdKabupaten string  `json:"kd_kabupaten"`
+	NmKabupaten string  `json:"nm_kabupaten"`
+	KdKecamatan string  `json:"kd_kecamatan"`
+	NmKecamatan string  `json:"nm_kecamatan"`
+	Score       float64 `json:"score,omitempty"`
 }
 
 // Kelurahan represents kelurahan data with full hierarchy
 type Kelurahan struct {
-	KdPropinsi  string `json:"kd_propinsi"`
-	NmPropinsi  string `json:"nm_propinsi"`
-	KdKabupaten string `json:"kd_kabupaten"`
-	NmKabupaten string `json:"nm_kabupaten"`
-	KdKecamatan string `json:"kd_kecamatan"`
-	NmKecamatan string `json:"nm_kecamatan"`
-	KdKelurahan string `json:"kd_kelurahan"`
-	NmKelurahan string `json:"nm_kelurahan"`
+	KdPropinsi  string  `json:"kd_propinsi"`
+	NmPropinsi  string  `json:"nm_propinsi"`
+	KdKabupaten string  `json:"kd_kabupaten"`
+	NmKabupaten string  `json:"nm_kabupaten"`
+	KdKecamatan string  `json:"kd_kecamatan"`
+	NmKecamatan string  `json:"nm_kecamatan"`
+	KdKelurahan string  `json:"kd_kelurahan"`
+	NmKelurahan string  `json:"nm_kelurahan"`
+	Score       float64 `json:"score,omitempty"`
+}
+
+// SearchResult represents a cross-level search result with breadcrumb
+type SearchResult struct {
+	Level      string  `json:"level"`
+	Code       string  `json:"code"`
+	Name       string  `json:"name"`
+	Breadcrumb string  `json:"breadcrumb"`
+	Score      float64 `json:"score"`
 }
 
-// GeoJSONFeature represents GeoJSON Feature format
+// GeoJSONFeature represents GeoJSON Feature format.
+// Geometry berisi GeoJSON dari ST_AsGeoJSON apa adanya, tanpa di-decode dan di-encode ulang.
 type GeoJSONFeature struct {
-	Type       string                 `json:"type"`
-	Properties GeoJSONProperties      `json:"properties"`
-	Geometry   map[string]interface{} `json:"geometry"`
+	Type       string            `json:"type"`
+	Properties GeoJSONProperties `json:"properties"`
+	Geometry   json.RawMessage   `json:"geometry" swaggertype:"object"`
 }
 
-// GeoJSONProperties represents properties dalam GeoJSON Feature
+// GeoJSONProperties represents properties dalam GeoJSON Feature.
+// Kode dan nama parent hanya diisi pada FeatureCollection wilayah anak.
 type GeoJSONProperties struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
-	Type string `json:"type"`
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	KdPropinsi  string `json:"kd_propinsi,omitempty"`
+	NmPropinsi  string `json:"nm_propinsi,omitempty"`
+	KdKabupaten string `json:"kd_kabupaten,omitempty"`
+	NmKabupaten string `json:"nm_kabupaten,omitempty"`
+	KdKecamatan string `json:"kd_kecamatan,omitempty"`
+	NmKecamatan string `json:"nm_kecamatan,omitempty"`
+}
+
+// DatasetVersion represents versi data wilayah, naik setiap kali tabel wilayah berubah
+type DatasetVersion struct {
+	Version   int64     `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ExportRow represents one region streamed by the export endpoint and CLI
+type ExportRow struct {
+	Level      string
+	Code       string
+	Properties GeoJSONProperties
+	// Geometry berisi GeoJSON atau WKT sesuai encoding yang diminta
+	Geometry string
+}
+
+// GeoJSONFeatureCollection represents GeoJSON FeatureCollection format
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// ReversePoint represents satu titik input untuk batch reverse geocoding
+type ReversePoint struct {
+	ID  string   `json:"id"`
+	Lat *float64 `json:"lat"`
+	Lon *float64 `json:"lon"`
+}
+
+// ReverseResult represents hasil reverse geocoding per titik dalam batch
+type ReverseResult struct {
+	ID     string     `json:"id"`
+	Result *Kelurahan `json:"result,omitempty"`
+	// Code berisi kode error yang sama dengan ErrorResponse.Code, mis. NOT_FOUND atau TIMEOUT
+	Code  string `json:"code,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// TileJSON represents TileJSON 3.0.0 descriptor untuk vector tile
+type TileJSON struct {
+	TileJSON     string        `json:"tilejson"`
+	Name         string        `json:"name"`
+	Scheme       string        `json:"scheme"`
+	Tiles        []string      `json:"tiles"`
+	MinZoom      int           `json:"minzoom"`
+	MaxZoom      int           `json:"maxzoom"`
+	Bounds       []float64     `json:"bounds"`
+	VectorLayers []VectorLayer `json:"vector_layers"`
+}
+
+// VectorLayer represents satu layer dalam TileJSON
+type VectorLayer struct {
+	ID      string            `json:"id"`
+	Fields  map[string]string `json:"fields"`
+	MinZoom int               `json:"minzoom"`
+	MaxZoom int               `json:"maxzoom"`
+}
+
+// ErrorResponse represents body response untuk semua error
+type ErrorResponse struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// PageParams berisi parameter pagination untuk endpoint search
+type PageParams struct {
+	Limit  int
+	Offset int
+	Cursor string
+}
+
+// Page represents envelope response endpoint search dengan pagination
+type Page[T any] struct {
+	Data       []T    `json:"data"`
+	Total      int    `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// HealthReport represents hasil pemeriksaan /livez dan /readyz
+type HealthReport struct {
+	Status    string                 `json:"status" example:"OK"`
+	LatencyMs float64                `json:"latency_ms"`
+	Checks    map[string]HealthCheck `json:"checks,omitempty"`
+}
+
+// HealthCheck represents hasil satu pemeriksaan readiness
+type HealthCheck struct {
+	Status    string      `json:"status" example:"OK"`
+	LatencyMs float64     `json:"latency_ms"`
+	Detail    interface{} `json:"detail,omitempty" swaggertype:"object"`
+	Error     string      `json:"error,omitempty"`
 }
@@ -0,0 +1,206 @@
+package repositories
+
+import (
+	"context"
+	"location-svc/internal/apperror"
+	"location-svc/internal/cache"
+	"location-svc/internal/models"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CachedStore membungkus LocationStore dengan cache in-memory untuk GeoJSON satu wilayah.
+// Batas wilayah hampir tidak pernah berubah, sehingga hasil Get*GeoJSON disimpan per
+// level, ID dan tolerance (handler sudah membulatkannya dengan QuantizeTolerance);
+// method lain diteruskan langsung ke store di bawahnya.
+// Cache dikosongkan saat DatasetVersion melihat versi dataset baru (mis. setelah import);
+// CachedGeoJSON sendiri membaca ulang versi paling lama setiap versionCheckInterval.
+type CachedStore struct {
+	LocationStore
+	features *cache.Cache[*CachedFeature]
+	versions *cache.Cache[*models.DatasetVersion]
+
+	mu      sync.Mutex
+	version int64
+}
+
+// NewCachedStore membuat CachedStore di atas store
+func NewCachedStore(store LocationStore, opts cache.Options) *CachedStore {
+	return &CachedStore{
+		LocationStore: store,
+		features:      cache.New[*CachedFeature](opts),
+		versions:      cache.New[*models.DatasetVersion](cache.Options{MaxEntries: 1, TTL: versionCheckInterval}),
+	}
+}
+
+// versionCheckInterval adalah jarak maksimum antar pembacaan versi dataset oleh CachedGeoJSON
+const versionCheckInterval = 5 * time.Second
+
+// CacheStats mengembalikan counter hit/miss cache GeoJSON
+func (s *CachedStore) CacheStats() cache.Stats {
+	return s.features.Stats()
+}
+
+// PurgeCache menghapus semua GeoJSON yang tersimpan
+func (s *CachedStore) PurgeCache() {
+	s.features.Purge()
+}
+
+// DatasetVersion mendapatkan versi dataset dari store dan mengosongkan cache jika versinya berubah
+func (s *CachedStore) DatasetVersion(ctx context.Context) (*models.DatasetVersion, error) {
+	v, err := s.LocationStore.DatasetVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.version != 0 && s.version != v.Version {
+		s.features.Purge()
+	}
+	s.version = v.Version
+	s.mu.Unlock()
+
+	return v, nil
+}
+
+// checkVersion membaca versi dataset jika pembacaan terakhir sudah lebih lama dari
+// versionCheckInterval, sehingga cache dikosongkan setelah import walaupun tidak ada
+// pemanggil DatasetVersion lain
+func (s *CachedStore) checkVersion(ctx context.Context) {
+	s.versions.Get("", func() (*models.DatasetVersion, error) {
+		v, err := s.DatasetVersion(context.WithoutCancel(ctx))
+		if err != nil {
+			// Kegagalan juga disimpan agar tidak setiap request mencoba ulang; entry lama tetap dipakai
+			return nil, nil
+		}
+		return v, nil
+	})
+}
+
+// CachedFeature adalah GeoJSON Feature di cache beserta body response yang sudah di-encode
+// per Content-Encoding, sehingga wilayah populer tidak di-encode dan dikompresi ulang di setiap request
+type CachedFeature struct {
+	Feature *models.GeoJSONFeature
+
+	// grown dipanggil setelah body baru disimpan agar cache menghitung ulang ukuran entry
+	grown func()
+
+	mu         sync.Mutex
+	bodies     map[string][]byte
+	jsonLength int
+}
+
+// featureOverhead adalah perkiraan ukuran properties, key dan struktur entry di luar geometry dan body
+const featureOverhead = 512
+
+// Size mengembalikan perkiraan memori entry: geometry ditambah semua body yang sudah di-encode
+func (f *CachedFeature) Size() int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	size := int64(featureOverhead)
+	if f.Feature != nil {
+		size += int64(len(f.Feature.Geometry))
+	}
+	for _, b := range f.bodies {
+		size += int64(len(b))
+	}
+	return size
+}
+
+// Body mengembalikan JSON Feature yang sudah dikompresi untuk encoding; compress hanya dipanggil
+// sekali per encoding selama entry ada di cache. Error tidak disimpan.
+func (f *CachedFeature) Body(encoding string, compress func(json []byte) ([]byte, error)) ([]byte, error) {
+	b, stored, err := f.body(encoding, compress)
+	if stored && f.grown != nil {
+		f.grown()
+	}
+	return b, err
+}
+
+// JSONLength mengembalikan panjang JSON Feature sebelum dikompresi; 0 sebelum Body pertama kali berhasil
+func (f *CachedFeature) JSONLength() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.jsonLength
+}
+
+func (f *CachedFeature) body(encoding string, compress func([]byte) ([]byte, error)) (b []byte, stored bool, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if b, ok := f.bodies[encoding]; ok {
+		return b, false, nil
+	}
+	b, err = f.Feature.AppendJSON(make([]byte, 0, len(f.Feature.Geometry)+512))
+	if err != nil {
+		return nil, false, err
+	}
+	f.jsonLength = len(b)
+	if b, err = compress(b); err != nil {
+		return nil, false, err
+	}
+	if f.bodies == nil {
+		f.bodies = make(map[string][]byte)
+	}
+	f.bodies[encoding] = b
+	return b, true, nil
+}
+
+// cachedLoaders memetakan level ke method store yang mengambil GeoJSON satu wilayah
+var cachedLoaders = map[string]func(LocationStore, context.Context, string, float64) (*models.GeoJSONFeature, error){
+	"propinsi":  LocationStore.GetPropinsiGeoJSON,
+	"kabupaten": LocationStore.GetKabupatenGeoJSON,
+	"kecamatan": LocationStore.GetKecamatanGeoJSON,
+	"kelurahan": LocationStore.GetKelurahanGeoJSON,
+}
+
+// CachedGeoJSON mendapatkan entry cache GeoJSON satu wilayah, mengambilnya dari store jika belum ada.
+// Load dipakai bersama request lain yang menunggu key yang sama, sehingga tidak ikut dibatalkan
+// saat client yang memicunya terputus; timeout query tetap berlaku di store. Hasil load yang
+// masih berjalan saat versi dataset berubah tidak disimpan.
+func (s *CachedStore) CachedGeoJSON(ctx context.Context, level, id string, tolerance float64) (*CachedFeature, error) {
+	load, ok := cachedLoaders[level]
+	if !ok {
+		return nil, apperror.InvalidArgument("Unknown level: " + level)
+	}
+	s.checkVersion(ctx)
+
+	key := level + "/" + id + "@" + strconv.FormatFloat(tolerance, 'g', -1, 64)
+	return s.features.Get(key, func() (*CachedFeature, error) {
+		feature, err := load(s.LocationStore, context.WithoutCancel(ctx), id, tolerance)
+		if err != nil {
+			return nil, err
+		}
+		return &CachedFeature{Feature: feature, grown: func() { s.features.Resize(key) }}, nil
+	})
+}
+
+// GetPropinsiGeoJSON mendapatkan GeoJSON propinsi dari cache atau store
+func (s *CachedStore) GetPropinsiGeoJSON(ctx context.Context, id string, tolerance float64) (*models.GeoJSONFeature, error) {
+	return s.feature(ctx, "propinsi", id, tolerance)
+}
+
+// GetKabupatenGeoJSON mendapatkan GeoJSON kabupaten dari cache atau store
+func (s *CachedStore) GetKabupatenGeoJSON(ctx context.Context, id string, tolerance float64) (*models.GeoJSONFeature, error) {
+	return s.feature(ctx, "kabupaten", id, tolerance)
+}
+
+// GetKecamatanGeoJSON mendapatkan GeoJSON kecamatan dari cache atau store
+func (s *CachedStore) GetKecamatanGeoJSON(ctx context.Context, id string, tolerance float64) (*models.GeoJSONFeature, error) {
+	return s.feature(ctx, "kecamatan", id, tolerance)
+}
+
+// GetKelurahanGeoJSON mendapatkan GeoJSON kelurahan dari cache atau store
+func (s *CachedStore) GetKelurahanGeoJSON(ctx context.Context, id string, tolerance float64) (*models.GeoJSONFeature, error) {
+	return s.feature(ctx, "kelurahan", id, tolerance)
+}
+
+func (s *CachedStore) feature(ctx context.Context, level, id string, tolerance float64) (*models.GeoJSONFeature, error) {
+	entry, err := s.CachedGeoJSON(ctx, level, id, tolerance)
+	if err != nil {
+		return nil, err
+	}
+	return entry.Feature, nil
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"location-svc/internal/apperror"
+	"net"
+
+	"github.com/lib/pq"
+)
+
+// dbError mengubah error dari database/sql dan lib/pq menjadi *apperror.Error
+// sehingga handler dapat memetakannya ke HTTP status yang tepat.
+func dbError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := apperror.As(err); ok {
+		return err
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return apperror.Wrap(apperror.CodeTimeout, "Database query timed out", err)
+	}
+	if errors.Is(err, context.Canceled) {
+		return canceledError(err)
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		// query_canceled, termasuk karena statement_timeout
+		case pqErr.Code == "57014":
+			return apperror.Wrap(apperror.CodeTimeout, "Database query timed out", err)
+		// connection_exception, admin_shutdown, crash_shutdown, cannot_connect_now
+		case pqErr.Code.Class() == "08" || pqErr.Code == "57P01" || pqErr.Code == "57P02" || pqErr.Code == "57P03":
+			return apperror.Wrap(apperror.CodeUnavailable, "Database is unavailable", err)
+		// data_exception, mis. parameter yang tidak bisa di-cast ke tipe kolom
+		case pqErr.Code.Class() == "22":
+			return apperror.Wrap(apperror.CodeInvalidArgument, "Invalid parameter value", err)
+		}
+	}
+
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.As(err, &netErr) {
+		return apperror.Wrap(apperror.CodeUnavailable, "Database is unavailable", err)
+	}
+
+	return apperror.Wrap(apperror.CodeInternal, "Database query failed", err)
+}
+
+// canceledError menandai query yang dibatalkan karena client memutus koneksi
+func canceledError(err error) error {
+	return apperror.Wrap(apperror.CodeCanceled, "Request canceled by client", err)
+}
+
+// ctxError mengganti err menjadi apperror.CodeCanceled jika ctx dibatalkan pemanggil. lib/pq
+// melaporkan pembatalan query sebagai query_canceled (57014) yang oleh dbError dianggap timeout,
+// sehingga penyebab sebenarnya hanya bisa dibaca dari ctx.
+func ctxError(ctx context.Context, err error) error {
+	if err == nil || !errors.Is(ctx.Err(), context.Canceled) {
+		return err
+	}
+	if appErr, ok := apperror.As(err); ok && appErr.Code == apperror.CodeCanceled {
+		return err
+	}
+	return canceledError(err)
+}
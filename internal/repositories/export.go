@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"location-svc/internal/apperror"
+	"location-svc/internal/models"
+	"strconv"
+)
+
+// GeometryEncoding menentukan format geometri pada ExportRow
+type GeometryEncoding string
+
+const (
+	GeometryGeoJSON GeometryEncoding = "geojson"
+	GeometryWKT     GeometryEncoding = "wkt"
+)
+
+// exportLevel mendeskripsikan query export satu level
+type exportLevel struct {
+	query     pageQuery
+	geomCol   string
+	parentCol string
+}
+
+var exportLevels = map[string]exportLevel{
+	"propinsi":  {query: propinsiSearch, geomCol: "geom"},
+	"kabupaten": {query: kabupatenSearch, geomCol: "k.geom", parentCol: "k.kd_propinsi"},
+	"kecamatan": {query: kecamatanSearch, geomCol: "kec.geom", parentCol: "kec.kd_kabupaten"},
+	"kelurahan": {query: kelurahanSearch, geomCol: "kel.geom", parentCol: "kel.kd_kecamatan"},
+}
+
+// validateExport memastikan level dikenal dan filter induk hanya dipakai pada level yang punya induk
+func validateExport(level, parentID string) (exportLevel, error) {
+	l, ok := exportLevels[level]
+	if !ok {
+		return l, apperror.InvalidArgument("level must be propinsi, kabupaten, kecamatan or kelurahan")
+	}
+	if parentID != "" && l.parentCol == "" {
+		return l, apperror.InvalidArgument("propinsi cannot be filtered by parent")
+	}
+	return l, nil
+}
+
+// ExportLevel memanggil fn untuk setiap wilayah satu level (opsional hanya anak dari parentID),
+// terurut berdasarkan kode. Baris dibaca satu per satu dari cursor database sehingga seluruh
+// level tidak pernah dimuat ke memori. Error dari fn menghentikan export dan dikembalikan apa adanya.
+func (r *LocationRepository) ExportLevel(ctx context.Context, level, parentID string, tolerance float64, encoding GeometryEncoding,
+	fn func(models.ExportRow) error) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Export)
+	defer cancel()
+
+	l, err := validateExport(level, parentID)
+	if err != nil {
+		return err
+	}
+
+	// Geometri NULL menjadi null (GeoJSON) atau string kosong (WKT)
+	geometry := "COALESCE(ST_AsGeoJSON(" + simplifyExpr(l.geomCol, 1) + "), 'null')"
+	if encoding == GeometryWKT {
+		geometry = "COALESCE(ST_AsText(" + simplifyExpr(l.geomCol, 1) + "), '')"
+	}
+
+	// Tolerance simplifyExpr selalu $1, filter induk menjadi $2
+	filter := searchFilter{args: []interface{}{tolerance}}
+	if parentID != "" {
+		filter.add(l.parentCol+" = ?", parentID)
+	}
+
+	query := "SELECT " + l.query.columns + ", " + geometry + " " + l.query.from + filter.where() + " ORDER BY " + l.query.codeCol
+
+	// Kolom berupa pasangan kode/nama dari propinsi sampai level itu sendiri, lalu geometri
+	values := make([]string, 2*(exportDepth(level)+1)+1)
+	dest := make([]interface{}, len(values))
+	for i := range dest {
+		dest[i] = &values[i]
+	}
+
+	sent := 0
+	return r.read(ctx, func(q queryer) error {
+		rows, err := q.QueryContext(ctx, query, filter.args...)
+		if err != nil {
+			return dbError(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			if err := rows.Scan(dest...); err != nil {
+				return streamed(sent, dbError(err))
+			}
+
+			row, err := exportRow(level, values[:len(values)-1], values[len(values)-1])
+			if err != nil {
+				return streamed(sent, err)
+			}
+
+			sent++
+			if err := fn(row); err != nil {
+				return streamed(sent, err)
+			}
+		}
+
+		return streamed(sent, dbError(rows.Err()))
+	})
+}
+
+// exportLevelOrder adalah urutan level dari tertinggi ke terendah
+var exportLevelOrder = []string{"propinsi", "kabupaten", "kecamatan", "kelurahan"}
+
+func exportDepth(level string) int {
+	for i, l := range exportLevelOrder {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// exportRow menyusun ExportRow dari pasangan kode/nama hierarki (propinsi lebih dulu). Kode yang
+// bukan angka menjadi error karena properti id GeoJSON berupa angka, sama seperti endpoint /geojson.
+func exportRow(level string, hierarchy []string, geometry string) (models.ExportRow, error) {
+	n := len(hierarchy)
+	code, name := hierarchy[n-2], hierarchy[n-1]
+	id, err := strconv.Atoi(code)
+	if err != nil {
+		return models.ExportRow{}, apperror.Wrap(apperror.CodeInternal, fmt.Sprintf("Invalid %s code %q", level, code), err)
+	}
+
+	props := models.GeoJSONProperties{ID: id, Name: name, Type: level}
+	parents := []struct{ kd, nm *string }{
+		{&props.KdPropinsi, &props.NmPropinsi},
+		{&props.KdKabupaten, &props.NmKabupaten},
+		{&props.KdKecamatan, &props.NmKecamatan},
+	}
+	for i := 0; i+2 < n; i += 2 {
+		*parents[i/2].kd, *parents[i/2].nm = hierarchy[i], hierarchy[i+1]
+	}
+
+	return models.ExportRow{Level: level, Code: code, Properties: props, Geometry: geometry}, nil
+}
@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"location-svc/internal/models"
+	"time"
+)
+
+// QueryObserver menerima nama method repository, durasinya dan error yang dikembalikan (nil jika sukses)
+type QueryObserver func(method string, duration time.Duration, err error)
+
+// InstrumentedStore membungkus LocationStore dan melaporkan durasi setiap pemanggilan method
+// ke observer, mis. untuk metrics atau log query lambat. Store yang dibungkus tidak di-embed
+// agar method baru di LocationStore wajib ditambahkan di sini juga.
+type InstrumentedStore struct {
+	store   LocationStore
+	observe QueryObserver
+}
+
+// NewInstrumentedStore membuat InstrumentedStore di atas store
+func NewInstrumentedStore(store LocationStore, observe QueryObserver) *InstrumentedStore {
+	return &InstrumentedStore{store: store, observe: observe}
+}
+
+// done melaporkan satu pemanggilan ke observer. Error dari request yang dibatalkan client
+// dilaporkan sebagai apperror.CodeCanceled, juga ke pemanggil, bukan sebagai timeout.
+func (s *InstrumentedStore) done(ctx context.Context, method string, start time.Time, err *error) {
+	*err = ctxError(ctx, *err)
+	s.observe(method, time.Since(start), *err)
+}
+
+func (s *InstrumentedStore) GetPropinsi(ctx context.Context, page models.PageParams) (result *models.Page[models.Propinsi], err error) {
+	defer s.done(ctx, "GetPropinsi", time.Now(), &err)
+	return s.store.GetPropinsi(ctx, page)
+}
+
+func (s *InstrumentedStore) SearchPropinsiByName(ctx context.Context, name string, page models.PageParams) (result *models.Page[models.Propinsi], err error) {
+	defer s.done(ctx, "SearchPropinsiByName", time.Now(), &err)
+	return s.store.SearchPropinsiByName(ctx, name, page)
+}
+
+func (s *InstrumentedStore) GetKabupaten(ctx context.Context, propinsiID string, page models.PageParams) (result *models.Page[models.Kabupaten], err error) {
+	defer s.done(ctx, "GetKabupaten", time.Now(), &err)
+	return s.store.GetKabupaten(ctx, propinsiID, page)
+}
+
+func (s *InstrumentedStore) SearchKabupatenByName(ctx context.Context, name string, propinsiID *string, page models.PageParams) (result *models.Page[models.Kabupaten], err error) {
+	defer s.done(ctx, "SearchKabupatenByName", time.Now(), &err)
+	return s.store.SearchKabupatenByName(ctx, name, propinsiID, page)
+}
+
+func (s *InstrumentedStore) GetKecamatan(ctx context.Context, propinsiID, kabupatenID string, page models.PageParams) (result *models.Page[models.Kecamatan], err error) {
+	defer s.done(ctx, "GetKecamatan", time.Now(), &err)
+	return s.store.GetKecamatan(ctx, propinsiID, kabupatenID, page)
+}
+
+func (s *InstrumentedStore) SearchKecamatanByName(ctx context.Context, name string, propinsiID, kabupatenID *string, page models.PageParams) (result *models.Page[models.Kecamatan], err error) {
+	defer s.done(ctx, "SearchKecamatanByName", time.Now(), &err)
+	return s.store.SearchKecamatanByName(ctx, name, propinsiID, kabupatenID, page)
+}
+
+func (s *InstrumentedStore) GetKelurahan(ctx context.Context, propinsiID, kabupatenID, kecamatanID string, page models.PageParams) (result *models.Page[models.Kelurahan], err error) {
+	defer s.done(ctx, "GetKelurahan", time.Now(), &err)
+	return s.store.GetKelurahan(ctx, propinsiID, kabupatenID, kecamatanID, page)
+}
+
+func (s *InstrumentedStore) SearchKelurahanByName(ctx context.Context, name string, propinsiID, kabupatenID, kecamatanID *string, page models.PageParams) (result *models.Page[models.Kelurahan], err error) {
+	defer s.done(ctx, "SearchKelurahanByName", time.Now(), &err)
+	return s.store.SearchKelurahanByName(ctx, name, propinsiID, kabupatenID, kecamatanID, page)
+}
+
+func (s *InstrumentedStore) Search(ctx context.Context, q string, limit int) (result []models.SearchResult, err error) {
+	defer s.done(ctx, "Search", time.Now(), &err)
+	return s.store.Search(ctx, q, limit)
+}
+
+func (s *InstrumentedStore) GetPropinsiGeoJSON(ctx context.Context, id string, tolerance float64) (result *models.GeoJSONFeature, err error) {
+	defer s.done(ctx, "GetPropinsiGeoJSON", time.Now(), &err)
+	return s.store.GetPropinsiGeoJSON(ctx, id, tolerance)
+}
+
+func (s *InstrumentedStore) GetKabupatenGeoJSON(ctx context.Context, id string, tolerance float64) (result *models.GeoJSONFeature, err error) {
+	defer s.done(ctx, "GetKabupatenGeoJSON", time.Now(), &err)
+	return s.store.GetKabupatenGeoJSON(ctx, id, tolerance)
+}
+
+func (s *InstrumentedStore) GetKecamatanGeoJSON(ctx context.Context, id string, tolerance float64) (result *models.GeoJSONFeature, err error) {
+	defer s.done(ctx, "GetKecamatanGeoJSON", time.Now(), &err)
+	return s.store.GetKecamatanGeoJSON(ctx, id, tolerance)
+}
+
+func (s *InstrumentedStore) GetKelurahanGeoJSON(ctx context.Context, id string, tolerance float64) (result *models.GeoJSONFeature, err error) {
+	defer s.done(ctx, "GetKelurahanGeoJSON", time.Now(), &err)
+	return s.store.GetKelurahanGeoJSON(ctx, id, tolerance)
+}
+
+func (s *InstrumentedStore) GetKabupatenFeatureCollection(ctx context.Context, propinsiID string, tolerance float64, fn func(*models.GeoJSONFeature) error) (err error) {
+	defer s.done(ctx, "GetKabupatenFeatureCollection", time.Now(), &err)
+	return s.store.GetKabupatenFeatureCollection(ctx, propinsiID, tolerance, fn)
+}
+
+func (s *InstrumentedStore) GetKecamatanFeatureCollection(ctx context.Context, kabupatenID string, tolerance float64, fn func(*models.GeoJSONFeature) error) (err error) {
+	defer s.done(ctx, "GetKecamatanFeatureCollection", time.Now(), &err)
+	return s.store.GetKecamatanFeatureCollection(ctx, kabupatenID, tolerance, fn)
+}
+
+func (s *InstrumentedStore) GetKelurahanFeatureCollection(ctx context.Context, kecamatanID string, tolerance float64, fn func(*models.GeoJSONFeature) error) (err error) {
+	defer s.done(ctx, "GetKelurahanFeatureCollection", time.Now(), &err)
+	return s.store.GetKelurahanFeatureCollection(ctx, kecamatanID, tolerance, fn)
+}
+
+func (s *InstrumentedStore) ReverseGeocode(ctx context.Context, lat, lon float64) (result *models.Kelurahan, err error) {
+	defer s.done(ctx, "ReverseGeocode", time.Now(), &err)
+	return s.store.ReverseGeocode(ctx, lat, lon)
+}
+
+func (s *InstrumentedStore) BatchReverseGeocode(ctx context.Context, lats, lons []float64) (result []*models.Kelurahan, err error) {
+	defer s.done(ctx, "BatchReverseGeocode", time.Now(), &err)
+	return s.store.BatchReverseGeocode(ctx, lats, lons)
+}
+
+func (s *InstrumentedStore) GetTile(ctx context.Context, level string, z, x, y int) (result []byte, err error) {
+	defer s.done(ctx, "GetTile", time.Now(), &err)
+	return s.store.GetTile(ctx, level, z, x, y)
+}
+
+func (s *InstrumentedStore) DatasetVersion(ctx context.Context) (result *models.DatasetVersion, err error) {
+	defer s.done(ctx, "DatasetVersion", time.Now(), &err)
+	return s.store.DatasetVersion(ctx)
+}
+
+func (s *InstrumentedStore) ExportLevel(ctx context.Context, level, parentID string, tolerance float64, encoding GeometryEncoding, fn func(models.ExportRow) error) (err error) {
+	defer s.done(ctx, "ExportLevel", time.Now(), &err)
+	return s.store.ExportLevel(ctx, level, parentID, tolerance, encoding, fn)
+}
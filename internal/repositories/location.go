@@ -1,14 +1,25 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"location-svc/internal/apperror"
 	"location-svc/internal/models"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 type LocationRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	router ReadRouter
+
+	timeouts  Timeouts
+	slowQuery time.Duration
 }
 
 // NewLocationRepository creates new instance of LocationRepository
@@ -16,369 +27,581 @@ func NewLocationRepository(db *sql.DB) *LocationRepository {
 	return &LocationRepository{db: db}
 }
 
-// GetPropinsi mendapatkan semua propinsi
-func (r *LocationRepository) GetPropinsi() ([]models.Propinsi, error) {
-	query := `SELECT kd_propinsi, nm_propinsi FROM propinsi ORDER BY nm_propinsi`
-
-	rows, err := r.db.Query(query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var provinces []models.Propinsi
-	for rows.Next() {
-		var p models.Propinsi
-		if err := rows.Scan(&p.KdPropinsi, &p.NmPropinsi); err != nil {
-			return nil, err
-		}
-		provinces = append(provinces, p)
-	}
+// fuzzyThreshold adalah batas minimum word_similarity pg_trgm agar nama dianggap cocok,
+// cukup rendah untuk salah ketik seperti "Sukabumy" atau "Jogjakarta" namun tidak mencocokkan
+// query pendek yang hanya sama di huruf awal kata
+const fuzzyThreshold = 0.45
 
-	return provinces, nil
+// GetPropinsi mendapatkan semua propinsi
+func (r *LocationRepository) GetPropinsi(ctx context.Context, page models.PageParams) (*models.Page[models.Propinsi], error) {
+	return r.SearchPropinsiByName(ctx, "", page)
 }
 
-// SearchPropinsiByName mencari propinsi berdasarkan nama
-func (r *LocationRepository) SearchPropinsiByName(name string) ([]models.Propinsi, error) {
-	query := `SELECT kd_propinsi, nm_propinsi FROM propinsi WHERE nm_propinsi ILIKE '%' || $1 || '%' ORDER BY nm_propinsi`
+// SearchPropinsiByName mencari propinsi berdasarkan nama (fuzzy, diurutkan berdasarkan relevansi),
+// nama kosong berarti semua propinsi
+func (r *LocationRepository) SearchPropinsiByName(ctx context.Context, name string, page models.PageParams) (*models.Page[models.Propinsi], error) {
+	var filter searchFilter
 
-	rows, err := r.db.Query(query, name)
-	if err != nil {
-		return nil, err
+	if name != "" {
+		filter.add(fuzzyCond("nm_propinsi"), name)
 	}
-	defer rows.Close()
 
-	var provinces []models.Propinsi
-	for rows.Next() {
+	return searchPage(ctx, r, propinsiSearch, filter, page, name != "", func(rows *sql.Rows, keys ...interface{}) (models.Propinsi, error) {
 		var p models.Propinsi
-		if err := rows.Scan(&p.KdPropinsi, &p.NmPropinsi); err != nil {
-			return nil, err
-		}
-		provinces = append(provinces, p)
-	}
-
-	return provinces, nil
+		err := rows.Scan(append([]interface{}{&p.KdPropinsi, &p.NmPropinsi, &p.Score}, keys...)...)
+		return p, err
+	})
 }
 
 // GetKabupaten mendapatkan kabupaten berdasarkan propinsi_id
-func (r *LocationRepository) GetKabupaten(propinsiID string) ([]models.Kabupaten, error) {
-	query := `
-		SELECT p.kd_propinsi, p.nm_propinsi, k.kd_kabupaten, k.nm_kabupaten 
-		FROM kabupaten k 
-		JOIN propinsi p ON k.kd_propinsi = p.kd_propinsi 
-		WHERE k.kd_propinsi = $1 
-		ORDER BY k.nm_kabupaten`
-
-	rows, err := r.db.Query(query, propinsiID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var kabupatens []models.Kabupaten
-	for rows.Next() {
-		var k models.Kabupaten
-		if err := rows.Scan(&k.KdPropinsi, &k.NmPropinsi, &k.KdKabupaten, &k.NmKabupaten); err != nil {
-			return nil, err
-		}
-		kabupatens = append(kabupatens, k)
-	}
-
-	return kabupatens, nil
+func (r *LocationRepository) GetKabupaten(ctx context.Context, propinsiID string, page models.PageParams) (*models.Page[models.Kabupaten], error) {
+	return r.SearchKabupatenByName(ctx, "", &propinsiID, page)
 }
 
 // SearchKabupatenByName mencari kabupaten berdasarkan nama dengan filter propinsi
-func (r *LocationRepository) SearchKabupatenByName(name string, propinsiID *string) ([]models.Kabupaten, error) {
-	var query string
-	var args []interface{}
+func (r *LocationRepository) SearchKabupatenByName(ctx context.Context, name string, propinsiID *string, page models.PageParams) (*models.Page[models.Kabupaten], error) {
+	var filter searchFilter
 
-	if propinsiID != nil {
-		query = `
-			SELECT p.kd_propinsi, p.nm_propinsi, k.kd_kabupaten, k.nm_kabupaten 
-			FROM kabupaten k 
-			JOIN propinsi p ON k.kd_propinsi = p.kd_propinsi 
-			WHERE k.nm_kabupaten ILIKE '%' || $1 || '%' AND k.kd_propinsi = $2
-			ORDER BY k.nm_kabupaten`
-		args = []interface{}{name, *propinsiID}
-	} else {
-		query = `
-			SELECT p.kd_propinsi, p.nm_propinsi, k.kd_kabupaten, k.nm_kabupaten 
-			FROM kabupaten k 
-			JOIN propinsi p ON k.kd_propinsi = p.kd_propinsi 
-			WHERE k.nm_kabupaten ILIKE '%' || $1 || '%'
-			ORDER BY k.nm_kabupaten`
-		args = []interface{}{name}
-	}
-
-	rows, err := r.db.Query(query, args...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var kabupatens []models.Kabupaten
-	for rows.Next() {
-		var k models.Kabupaten
-		if err := rows.Scan(&k.KdPropinsi, &k.NmPropinsi, &k.KdKabupaten, &k.NmKabupaten); err != nil {
-			return nil, err
-		}
-		kabupatens = append(kabupatens, k)
+	if name != "" {
+		filter.add(fuzzyCond("k.nm_kabupaten"), name)
 	}
 
-	return kabupatens, nil
-} // GetKecamatan mendapatkan kecamatan berdasarkan propinsi_id dan kabupaten_id
-func (r *LocationRepository) GetKecamatan(propinsiID, kabupatenID string) ([]models.Kecamatan, error) {
-	query := `
-		SELECT p.kd_propinsi, p.nm_propinsi, k.kd_kabupaten, k.nm_kabupaten, kec.kd_kecamatan, kec.nm_kecamatan
-		FROM kecamatan kec
-		JOIN kabupaten k ON kec.kd_kabupaten = k.kd_kabupaten
-		JOIN propinsi p ON k.kd_propinsi = p.kd_propinsi
-		WHERE k.kd_propinsi = $1 AND k.kd_kabupaten = $2
-		ORDER BY kec.nm_kecamatan`
-
-	rows, err := r.db.Query(query, propinsiID, kabupatenID)
-	if err != nil {
-		return nil, err
+	if propinsiID != nil {
+		filter.add("k.kd_propinsi = ?", *propinsiID)
 	}
-	defer rows.Close()
 
-	var kecamatans []models.Kecamatan
-	for rows.Next() {
-		var kec models.Kecamatan
-		if err := rows.Scan(&kec.KdPropinsi, &kec.NmPropinsi, &kec.KdKabupaten, &kec.NmKabupaten, &kec.KdKecamatan, &kec.NmKecamatan); err != nil {
-			return nil, err
-		}
-		kecamatans = append(kecamatans, kec)
-	}
+	return searchPage(ctx, r, kabupatenSearch, filter, page, name != "", func(rows *sql.Rows, keys ...interface{}) (models.Kabupaten, error) {
+		var k models.Kabupaten
+		err := rows.Scan(append([]interface{}{&k.KdPropinsi, &k.NmPropinsi, &k.KdKabupaten, &k.NmKabupaten, &k.Score}, keys...)...)
+		return k, err
+	})
+}
 
-	return kecamatans, nil
+// GetKecamatan mendapatkan kecamatan berdasarkan propinsi_id dan kabupaten_id
+func (r *LocationRepository) GetKecamatan(ctx context.Context, propinsiID, kabupatenID string, page models.PageParams) (*models.Page[models.Kecamatan], error) {
+	return r.SearchKecamatanByName(ctx, "", &propinsiID, &kabupatenID, page)
 }
 
 // SearchKecamatanByName mencari kecamatan berdasarkan nama dengan filter hierarki
-func (r *LocationRepository) SearchKecamatanByName(name string, propinsiID, kabupatenID *string) ([]models.Kecamatan, error) {
-	var query string
-	var args []interface{}
+func (r *LocationRepository) SearchKecamatanByName(ctx context.Context, name string, propinsiID, kabupatenID *string, page models.PageParams) (*models.Page[models.Kecamatan], error) {
+	var filter searchFilter
 
-	baseQuery := `
-		SELECT p.kd_propinsi, p.nm_propinsi, k.kd_kabupaten, k.nm_kabupaten, kec.kd_kecamatan, kec.nm_kecamatan
-		FROM kecamatan kec
-		JOIN kabupaten k ON kec.kd_kabupaten = k.kd_kabupaten
-		JOIN propinsi p ON k.kd_propinsi = p.kd_propinsi
-		WHERE kec.nm_kecamatan ILIKE '%' || $1 || '%'`
-
-	args = append(args, name)
+	if name != "" {
+		filter.add(fuzzyCond("kec.nm_kecamatan"), name)
+	}
 
 	if propinsiID != nil {
-		baseQuery += " AND k.kd_propinsi = $" + fmt.Sprintf("%d", len(args)+1)
-		args = append(args, *propinsiID)
+		filter.add("k.kd_propinsi = ?", *propinsiID)
 	}
 
 	if kabupatenID != nil {
-		baseQuery += " AND k.kd_kabupaten = $" + fmt.Sprintf("%d", len(args)+1)
-		args = append(args, *kabupatenID)
+		filter.add("k.kd_kabupaten = ?", *kabupatenID)
 	}
 
-	query = baseQuery + " ORDER BY kec.nm_kecamatan"
-
-	rows, err := r.db.Query(query, args...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var kecamatans []models.Kecamatan
-	for rows.Next() {
+	return searchPage(ctx, r, kecamatanSearch, filter, page, name != "", func(rows *sql.Rows, keys ...interface{}) (models.Kecamatan, error) {
 		var kec models.Kecamatan
-		if err := rows.Scan(&kec.KdPropinsi, &kec.NmPropinsi, &kec.KdKabupaten, &kec.NmKabupaten, &kec.KdKecamatan, &kec.NmKecamatan); err != nil {
-			return nil, err
-		}
-		kecamatans = append(kecamatans, kec)
-	}
-
-	return kecamatans, nil
-} // GetKelurahan mendapatkan kelurahan berdasarkan hierarki lengkap
-func (r *LocationRepository) GetKelurahan(propinsiID, kabupatenID, kecamatanID string) ([]models.Kelurahan, error) {
-	query := `
-		SELECT p.kd_propinsi, p.nm_propinsi, k.kd_kabupaten, k.nm_kabupaten, 
-		       kec.kd_kecamatan, kec.nm_kecamatan, kel.kd_kelurahan, kel.nm_kelurahan
-		FROM kelurahan kel
-		JOIN kecamatan kec ON kel.kd_kecamatan = kec.kd_kecamatan
-		JOIN kabupaten k ON kec.kd_kabupaten = k.kd_kabupaten
-		JOIN propinsi p ON k.kd_propinsi = p.kd_propinsi
-		WHERE p.kd_propinsi = $1 AND k.kd_kabupaten = $2 AND kec.kd_kecamatan = $3
-		ORDER BY kel.nm_kelurahan`
-
-	rows, err := r.db.Query(query, propinsiID, kabupatenID, kecamatanID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var kelurahans []models.Kelurahan
-	for rows.Next() {
-		var kel models.Kelurahan
-		if err := rows.Scan(&kel.KdPropinsi, &kel.NmPropinsi, &kel.KdKabupaten, &kel.NmKabupaten,
-			&kel.KdKecamatan, &kel.NmKecamatan, &kel.KdKelurahan, &kel.NmKelurahan); err != nil {
-			return nil, err
-		}
-		kelurahans = append(kelurahans, kel)
-	}
+		err := rows.Scan(append([]interface{}{&kec.KdPropinsi, &kec.NmPropinsi, &kec.KdKabupaten, &kec.NmKabupaten,
+			&kec.KdKecamatan, &kec.NmKecamatan, &kec.Score}, keys...)...)
+		return kec, err
+	})
+}
 
-	return kelurahans, nil
+// GetKelurahan mendapatkan kelurahan berdasarkan hierarki lengkap
+func (r *LocationRepository) GetKelurahan(ctx context.Context, propinsiID, kabupatenID, kecamatanID string, page models.PageParams) (*models.Page[models.Kelurahan], error) {
+	return r.SearchKelurahanByName(ctx, "", &propinsiID, &kabupatenID, &kecamatanID, page)
 }
 
 // SearchKelurahanByName mencari kelurahan berdasarkan nama dengan filter hierarki
-func (r *LocationRepository) SearchKelurahanByName(name string, propinsiID, kabupatenID, kecamatanID *string) ([]models.Kelurahan, error) {
-	var query string
-	var args []interface{}
-
-	baseQuery := `
-		SELECT p.kd_propinsi, p.nm_propinsi, k.kd_kabupaten, k.nm_kabupaten, 
-		       kec.kd_kecamatan, kec.nm_kecamatan, kel.kd_kelurahan, kel.nm_kelurahan
-		FROM kelurahan kel
-		JOIN kecamatan kec ON kel.kd_kecamatan = kec.kd_kecamatan
-		JOIN kabupaten k ON kec.kd_kabupaten = k.kd_kabupaten
-		JOIN propinsi p ON k.kd_propinsi = p.kd_propinsi
-		WHERE kel.nm_kelurahan ILIKE '%' || $1 || '%'`
+func (r *LocationRepository) SearchKelurahanByName(ctx context.Context, name string, propinsiID, kabupatenID, kecamatanID *string, page models.PageParams) (*models.Page[models.Kelurahan], error) {
+	var filter searchFilter
 
-	args = append(args, name)
+	if name != "" {
+		filter.add(fuzzyCond("kel.nm_kelurahan"), name)
+	}
 
 	if propinsiID != nil {
-		baseQuery += " AND p.kd_propinsi = $" + fmt.Sprintf("%d", len(args)+1)
-		args = append(args, *propinsiID)
+		filter.add("p.kd_propinsi = ?", *propinsiID)
 	}
 
 	if kabupatenID != nil {
-		baseQuery += " AND k.kd_kabupaten = $" + fmt.Sprintf("%d", len(args)+1)
-		args = append(args, *kabupatenID)
+		filter.add("k.kd_kabupaten = ?", *kabupatenID)
 	}
 
 	if kecamatanID != nil {
-		baseQuery += " AND kec.kd_kecamatan = $" + fmt.Sprintf("%d", len(args)+1)
-		args = append(args, *kecamatanID)
-	}
-
-	query = baseQuery + " ORDER BY kel.nm_kelurahan"
-
-	rows, err := r.db.Query(query, args...)
-	if err != nil {
-		return nil, err
+		filter.add("kec.kd_kecamatan = ?", *kecamatanID)
 	}
-	defer rows.Close()
 
-	var kelurahans []models.Kelurahan
-	for rows.Next() {
+	return searchPage(ctx, r, kelurahanSearch, filter, page, name != "", func(rows *sql.Rows, keys ...interface{}) (models.Kelurahan, error) {
 		var kel models.Kelurahan
-		if err := rows.Scan(&kel.KdPropinsi, &kel.NmPropinsi, &kel.KdKabupaten, &kel.NmKabupaten,
-			&kel.KdKecamatan, &kel.NmKecamatan, &kel.KdKelurahan, &kel.NmKelurahan); err != nil {
-			return nil, err
-		}
-		kelurahans = append(kelurahans, kel)
-	}
+		err := rows.Scan(append([]interface{}{&kel.KdPropinsi, &kel.NmPropinsi, &kel.KdKabupaten, &kel.NmKabupaten,
+			&kel.KdKecamatan, &kel.NmKecamatan, &kel.KdKelurahan, &kel.NmKelurahan, &kel.Score}, keys...)...)
+		return kel, err
+	})
+}
 
-	return kelurahans, nil
+// fuzzyCond mengembalikan kondisi pencarian nama: substring (ILIKE) atau mirip secara trigram.
+// Operator <% memakai GIN trigram index dengan threshold pg_trgm.word_similarity_threshold yang
+// diset db.Connect untuk setiap koneksi; word_similarity >= fuzzyThreshold membuat hasilnya
+// tetap sama walaupun threshold sesi berbeda, sehingga query tidak butuh transaksi atau set_config.
+// Kondisi ini harus menjadi filter pertama karena ekspresi relevansi merujuk nama sebagai $1.
+func fuzzyCond(nameCol string) string {
+	threshold := strconv.FormatFloat(fuzzyThreshold, 'f', -1, 64)
+	return "(" + nameCol + " ILIKE '%' || ? || '%' OR ($1 <% " + nameCol +
+		" AND word_similarity($1, " + nameCol + ") >= " + threshold + "))"
 }
 
-// GetPropinsiGeoJSON mendapatkan GeoJSON propinsi berdasarkan ID
-func (r *LocationRepository) GetPropinsiGeoJSON(id string) (*models.GeoJSONFeature, error) {
+// searchPage menjalankan queryPage dengan timeout Search
+func searchPage[T any](ctx context.Context, r *LocationRepository, q pageQuery, filter searchFilter, page models.PageParams, ranked bool,
+	scan func(rows *sql.Rows, keys ...interface{}) (T, error)) (*models.Page[T], error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Search)
+	defer cancel()
+
+	var result *models.Page[T]
+	err := r.read(ctx, func(db queryer) (err error) {
+		result, err = queryPage(ctx, db, q, filter, page, ranked, scan)
+		return err
+	})
+	return result, err
+}
+
+// GetPropinsiGeoJSON mendapatkan GeoJSON propinsi berdasarkan ID, disederhanakan jika tolerance > 0
+func (r *LocationRepository) GetPropinsiGeoJSON(ctx context.Context, id string, tolerance float64) (*models.GeoJSONFeature, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.GeoJSON)
+	defer cancel()
+
 	query := `
-		SELECT kd_propinsi, nm_propinsi, ST_AsGeoJSON(geom) as geometry 
+		SELECT kd_propinsi, nm_propinsi, ST_AsGeoJSON(` + simplifyExpr("geom", 2) + `) as geometry 
 		FROM propinsi 
 		WHERE kd_propinsi = $1
 	`
 
 	var feature models.GeoJSONFeature
-	var geometryJSON string
+	var geometry []byte
 
-	err := r.db.QueryRow(query, id).Scan(&feature.Properties.ID, &feature.Properties.Name, &geometryJSON)
+	err := r.read(ctx, func(q queryer) error {
+		return q.QueryRowContext(ctx, query, id, tolerance).Scan(&feature.Properties.ID, &feature.Properties.Name, &geometry)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperror.NotFound("Province not found")
+	}
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 
 	feature.Type = "Feature"
 	feature.Properties.Type = "propinsi"
-
-	if err := json.Unmarshal([]byte(geometryJSON), &feature.Geometry); err != nil {
-		return nil, err
-	}
+	feature.Geometry = geometry
 
 	return &feature, nil
 }
 
-// GetKabupatenGeoJSON mendapatkan GeoJSON kabupaten berdasarkan ID
-func (r *LocationRepository) GetKabupatenGeoJSON(id string) (*models.GeoJSONFeature, error) {
+// GetKabupatenGeoJSON mendapatkan GeoJSON kabupaten berdasarkan ID, disederhanakan jika tolerance > 0
+func (r *LocationRepository) GetKabupatenGeoJSON(ctx context.Context, id string, tolerance float64) (*models.GeoJSONFeature, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.GeoJSON)
+	defer cancel()
+
 	query := `
-		SELECT kd_kabupaten, nm_kabupaten, ST_AsGeoJSON(geom) as geometry 
+		SELECT kd_kabupaten, nm_kabupaten, ST_AsGeoJSON(` + simplifyExpr("geom", 2) + `) as geometry 
 		FROM kabupaten 
 		WHERE kd_kabupaten = $1
 	`
 
 	var feature models.GeoJSONFeature
-	var geometryJSON string
+	var geometry []byte
 
-	err := r.db.QueryRow(query, id).Scan(&feature.Properties.ID, &feature.Properties.Name, &geometryJSON)
+	err := r.read(ctx, func(q queryer) error {
+		return q.QueryRowContext(ctx, query, id, tolerance).Scan(&feature.Properties.ID, &feature.Properties.Name, &geometry)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperror.NotFound("Regency not found")
+	}
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 
 	feature.Type = "Feature"
 	feature.Properties.Type = "kabupaten"
-
-	if err := json.Unmarshal([]byte(geometryJSON), &feature.Geometry); err != nil {
-		return nil, err
-	}
+	feature.Geometry = geometry
 
 	return &feature, nil
 }
 
-// GetKecamatanGeoJSON mendapatkan GeoJSON kecamatan berdasarkan ID
-func (r *LocationRepository) GetKecamatanGeoJSON(id string) (*models.GeoJSONFeature, error) {
+// GetKecamatanGeoJSON mendapatkan GeoJSON kecamatan berdasarkan ID, disederhanakan jika tolerance > 0
+func (r *LocationRepository) GetKecamatanGeoJSON(ctx context.Context, id string, tolerance float64) (*models.GeoJSONFeature, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.GeoJSON)
+	defer cancel()
+
 	query := `
-		SELECT kd_kecamatan, nm_kecamatan, ST_AsGeoJSON(geom) as geometry 
+		SELECT kd_kecamatan, nm_kecamatan, ST_AsGeoJSON(` + simplifyExpr("geom", 2) + `) as geometry 
 		FROM kecamatan 
 		WHERE kd_kecamatan = $1
 	`
 
 	var feature models.GeoJSONFeature
-	var geometryJSON string
+	var geometry []byte
 
-	err := r.db.QueryRow(query, id).Scan(&feature.Properties.ID, &feature.Properties.Name, &geometryJSON)
+	err := r.read(ctx, func(q queryer) error {
+		return q.QueryRowContext(ctx, query, id, tolerance).Scan(&feature.Properties.ID, &feature.Properties.Name, &geometry)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperror.NotFound("District not found")
+	}
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 
 	feature.Type = "Feature"
 	feature.Properties.Type = "kecamatan"
-
-	if err := json.Unmarshal([]byte(geometryJSON), &feature.Geometry); err != nil {
-		return nil, err
-	}
+	feature.Geometry = geometry
 
 	return &feature, nil
 }
 
-// GetKelurahanGeoJSON mendapatkan GeoJSON kelurahan berdasarkan ID
-func (r *LocationRepository) GetKelurahanGeoJSON(id string) (*models.GeoJSONFeature, error) {
+// GetKelurahanGeoJSON mendapatkan GeoJSON kelurahan berdasarkan ID, disederhanakan jika tolerance > 0
+func (r *LocationRepository) GetKelurahanGeoJSON(ctx context.Context, id string, tolerance float64) (*models.GeoJSONFeature, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.GeoJSON)
+	defer cancel()
+
 	query := `
-		SELECT kd_kelurahan, nm_kelurahan, ST_AsGeoJSON(geom) as geometry 
+		SELECT kd_kelurahan, nm_kelurahan, ST_AsGeoJSON(` + simplifyExpr("geom", 2) + `) as geometry 
 		FROM kelurahan 
 		WHERE kd_kelurahan = $1
 	`
 
 	var feature models.GeoJSONFeature
-	var geometryJSON string
+	var geometry []byte
 
-	err := r.db.QueryRow(query, id).Scan(&feature.Properties.ID, &feature.Properties.Name, &geometryJSON)
+	err := r.read(ctx, func(q queryer) error {
+		return q.QueryRowContext(ctx, query, id, tolerance).Scan(&feature.Properties.ID, &feature.Properties.Name, &geometry)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperror.NotFound("Village not found")
+	}
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 
 	feature.Type = "Feature"
 	feature.Properties.Type = "kelurahan"
+	feature.Geometry = geometry
+
+	return &feature, nil
+}
+
+// ReverseGeocode mendapatkan hierarki kelurahan yang memuat titik koordinat (lat, lon).
+// Titik yang jatuh tepat di perbatasan akan menyentuh lebih dari satu kelurahan, sehingga
+// kelurahan yang benar-benar memuat titik diutamakan lalu diurutkan berdasarkan kode agar
+// hasilnya deterministik. Mengembalikan error NotFound jika titik berada di luar wilayah.
+func (r *LocationRepository) ReverseGeocode(ctx context.Context, lat, lon float64) (*models.Kelurahan, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Reverse)
+	defer cancel()
+
+	query := `
+		WITH pt AS (SELECT ST_SetSRID(ST_MakePoint($1, $2), 4326) AS geom)
+		SELECT p.kd_propinsi, p.nm_propinsi, k.kd_kabupaten, k.nm_kabupaten,
+		       kec.kd_kecamatan, kec.nm_kecamatan, kel.kd_kelurahan, kel.nm_kelurahan
+		FROM pt, kelurahan kel
+		JOIN kecamatan kec ON kel.kd_kecamatan = kec.kd_kecamatan
+		JOIN kabupaten k ON kec.kd_kabupaten = k.kd_kabupaten
+		JOIN propinsi p ON k.kd_propinsi = p.kd_propinsi
+		WHERE ST_Intersects(kel.geom, pt.geom)
+		ORDER BY ST_Contains(kel.geom, pt.geom) DESC, kel.kd_kelurahan
+		LIMIT 1`
+
+	var kel models.Kelurahan
+	err := r.read(ctx, func(q queryer) error {
+		return q.QueryRowContext(ctx, query, lon, lat).Scan(&kel.KdPropinsi, &kel.NmPropinsi, &kel.KdKabupaten, &kel.NmKabupaten,
+			&kel.KdKecamatan, &kel.NmKecamatan, &kel.KdKelurahan, &kel.NmKelurahan)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperror.NotFound("No region found at the given coordinate")
+	}
+	if err != nil {
+		return nil, dbError(err)
+	}
 
-	if err := json.Unmarshal([]byte(geometryJSON), &feature.Geometry); err != nil {
-		return nil, err
+	return &kel, nil
+}
+
+// BatchReverseGeocode mendapatkan hierarki kelurahan untuk sekumpulan titik dalam satu query.
+// lats dan lons harus memiliki panjang yang sama; hasil dikembalikan sesuai urutan input dan
+// bernilai nil untuk titik yang tidak berada di dalam wilayah manapun.
+func (r *LocationRepository) BatchReverseGeocode(ctx context.Context, lats, lons []float64) ([]*models.Kelurahan, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Reverse)
+	defer cancel()
+
+	if len(lats) != len(lons) {
+		return nil, apperror.InvalidArgument(fmt.Sprintf("lats and lons length mismatch: %d != %d", len(lats), len(lons)))
 	}
 
-	return &feature, nil
+	query := `
+		SELECT pts.idx, p.kd_propinsi, p.nm_propinsi, k.kd_kabupaten, k.nm_kabupaten,
+		       kec.kd_kecamatan, kec.nm_kecamatan, kel.kd_kelurahan, kel.nm_kelurahan
+		FROM unnest($1::float8[], $2::float8[]) WITH ORDINALITY AS pts(lon, lat, idx)
+		JOIN LATERAL (
+			SELECT k2.kd_kelurahan, k2.nm_kelurahan, k2.kd_kecamatan
+			FROM kelurahan k2
+			WHERE ST_Intersects(k2.geom, ST_SetSRID(ST_MakePoint(pts.lon, pts.lat), 4326))
+			ORDER BY ST_Contains(k2.geom, ST_SetSRID(ST_MakePoint(pts.lon, pts.lat), 4326)) DESC, k2.kd_kelurahan
+			LIMIT 1
+		) kel ON true
+		JOIN kecamatan kec ON kel.kd_kecamatan = kec.kd_kecamatan
+		JOIN kabupaten k ON kec.kd_kabupaten = k.kd_kabupaten
+		JOIN propinsi p ON k.kd_propinsi = p.kd_propinsi`
+
+	var results []*models.Kelurahan
+	err := r.read(ctx, func(q queryer) error {
+		rows, err := q.QueryContext(ctx, query, pq.Array(lons), pq.Array(lats))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		results = make([]*models.Kelurahan, len(lats))
+		for rows.Next() {
+			var idx int
+			var kel models.Kelurahan
+			if err := rows.Scan(&idx, &kel.KdPropinsi, &kel.NmPropinsi, &kel.KdKabupaten, &kel.NmKabupaten,
+				&kel.KdKecamatan, &kel.NmKecamatan, &kel.KdKelurahan, &kel.NmKelurahan); err != nil {
+				return err
+			}
+			// WITH ORDINALITY dimulai dari 1
+			results[idx-1] = &kel
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	return results, nil
+}
+
+// GetKabupatenFeatureCollection memanggil fn untuk GeoJSON setiap kabupaten dalam satu propinsi,
+// terurut berdasarkan kode. Baris dibaca satu per satu dari cursor database sehingga collection
+// tidak pernah dimuat utuh ke memori; error dari fn menghentikan query dan dikembalikan apa adanya.
+func (r *LocationRepository) GetKabupatenFeatureCollection(ctx context.Context, propinsiID string, tolerance float64, fn func(*models.GeoJSONFeature) error) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.GeoJSON)
+	defer cancel()
+
+	query := `
+		SELECT k.kd_kabupaten, k.nm_kabupaten, p.kd_propinsi, p.nm_propinsi, ST_AsGeoJSON(` + simplifyExpr("k.geom", 2) + `) as geometry
+		FROM kabupaten k
+		JOIN propinsi p ON k.kd_propinsi = p.kd_propinsi
+		WHERE k.kd_propinsi = $1
+		ORDER BY k.kd_kabupaten`
+
+	count := 0
+	err := r.read(ctx, func(q queryer) error {
+		rows, err := q.QueryContext(ctx, query, propinsiID, tolerance)
+		if err != nil {
+			return dbError(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var feature models.GeoJSONFeature
+			var geometry []byte
+			if err := rows.Scan(&feature.Properties.ID, &feature.Properties.Name,
+				&feature.Properties.KdPropinsi, &feature.Properties.NmPropinsi, &geometry); err != nil {
+				return streamed(count, dbError(err))
+			}
+
+			count++
+			if err := fn(newFeature(feature, "kabupaten", geometry)); err != nil {
+				return streamed(count, err)
+			}
+		}
+		return streamed(count, dbError(rows.Err()))
+	})
+	if err != nil {
+		return err
+	}
+
+	// Collection kosong bisa berarti parent tanpa anak atau parent yang tidak dikenal
+	if count == 0 {
+		return r.requireRegion(ctx, "propinsi", "kd_propinsi", propinsiID, "Province not found")
+	}
+	return nil
+}
+
+// GetKecamatanFeatureCollection memanggil fn untuk GeoJSON setiap kecamatan dalam satu kabupaten, seperti
+// GetKabupatenFeatureCollection
+func (r *LocationRepository) GetKecamatanFeatureCollection(ctx context.Context, kabupatenID string, tolerance float64, fn func(*models.GeoJSONFeature) error) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.GeoJSON)
+	defer cancel()
+
+	query := `
+		SELECT kec.kd_kecamatan, kec.nm_kecamatan, p.kd_propinsi, p.nm_propinsi,
+		       k.kd_kabupaten, k.nm_kabupaten, ST_AsGeoJSON(` + simplifyExpr("kec.geom", 2) + `) as geometry
+		FROM kecamatan kec
+		JOIN kabupaten k ON kec.kd_kabupaten = k.kd_kabupaten
+		JOIN propinsi p ON k.kd_propinsi = p.kd_propinsi
+		WHERE kec.kd_kabupaten = $1
+		ORDER BY kec.kd_kecamatan`
+
+	count := 0
+	err := r.read(ctx, func(q queryer) error {
+		rows, err := q.QueryContext(ctx, query, kabupatenID, tolerance)
+		if err != nil {
+			return dbError(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var feature models.GeoJSONFeature
+			var geometry []byte
+			if err := rows.Scan(&feature.Properties.ID, &feature.Properties.Name,
+				&feature.Properties.KdPropinsi, &feature.Properties.NmPropinsi,
+				&feature.Properties.KdKabupaten, &feature.Properties.NmKabupaten, &geometry); err != nil {
+				return streamed(count, dbError(err))
+			}
+
+			count++
+			if err := fn(newFeature(feature, "kecamatan", geometry)); err != nil {
+				return streamed(count, err)
+			}
+		}
+		return streamed(count, dbError(rows.Err()))
+	})
+	if err != nil {
+		return err
+	}
+
+	// Collection kosong bisa berarti parent tanpa anak atau parent yang tidak dikenal
+	if count == 0 {
+		return r.requireRegion(ctx, "kabupaten", "kd_kabupaten", kabupatenID, "Regency not found")
+	}
+	return nil
+}
+
+// GetKelurahanFeatureCollection memanggil fn untuk GeoJSON setiap kelurahan dalam satu kecamatan, seperti
+// GetKabupatenFeatureCollection
+func (r *LocationRepository) GetKelurahanFeatureCollection(ctx context.Context, kecamatanID string, tolerance float64, fn func(*models.GeoJSONFeature) error) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.GeoJSON)
+	defer cancel()
+
+	query := `
+		SELECT kel.kd_kelurahan, kel.nm_kelurahan, p.kd_propinsi, p.nm_propinsi,
+		       k.kd_kabupaten, k.nm_kabupaten, kec.kd_kecamatan, kec.nm_kecamatan,
+		       ST_AsGeoJSON(` + simplifyExpr("kel.geom", 2) + `) as geometry
+		FROM kelurahan kel
+		JOIN kecamatan kec ON kel.kd_kecamatan = kec.kd_kecamatan
+		JOIN kabupaten k ON kec.kd_kabupaten = k.kd_kabupaten
+		JOIN propinsi p ON k.kd_propinsi = p.kd_propinsi
+		WHERE kel.kd_kecamatan = $1
+		ORDER BY kel.kd_kelurahan`
+
+	count := 0
+	err := r.read(ctx, func(q queryer) error {
+		rows, err := q.QueryContext(ctx, query, kecamatanID, tolerance)
+		if err != nil {
+			return dbError(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var feature models.GeoJSONFeature
+			var geometry []byte
+			if err := rows.Scan(&feature.Properties.ID, &feature.Properties.Name,
+				&feature.Properties.KdPropinsi, &feature.Properties.NmPropinsi,
+				&feature.Properties.KdKabupaten, &feature.Properties.NmKabupaten,
+				&feature.Properties.KdKecamatan, &feature.Properties.NmKecamatan, &geometry); err != nil {
+				return streamed(count, dbError(err))
+			}
+
+			count++
+			if err := fn(newFeature(feature, "kelurahan", geometry)); err != nil {
+				return streamed(count, err)
+			}
+		}
+		return streamed(count, dbError(rows.Err()))
+	})
+	if err != nil {
+		return err
+	}
+
+	// Collection kosong bisa berarti parent tanpa anak atau parent yang tidak dikenal
+	if count == 0 {
+		return r.requireRegion(ctx, "kecamatan", "kd_kecamatan", kecamatanID, "District not found")
+	}
+	return nil
+}
+
+// requireRegion mengembalikan apperror.NotFound jika tidak ada baris di table dengan column = id
+func (r *LocationRepository) requireRegion(ctx context.Context, table, column, id, notFound string) error {
+	var exists bool
+	query := "SELECT EXISTS (SELECT 1 FROM " + table + " WHERE " + column + " = $1)"
+	err := r.read(ctx, func(q queryer) error {
+		return q.QueryRowContext(ctx, query, id).Scan(&exists)
+	})
+	if err != nil {
+		return dbError(err)
+	}
+	if !exists {
+		return apperror.NotFound(notFound)
+	}
+	return nil
+}
+
+// DatasetVersion mendapatkan versi data wilayah yang dinaikkan trigger setiap tabel wilayah berubah
+func (r *LocationRepository) DatasetVersion(ctx context.Context) (*models.DatasetVersion, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Search)
+	defer cancel()
+
+	var v models.DatasetVersion
+	err := r.read(ctx, func(q queryer) error {
+		return q.QueryRowContext(ctx, `SELECT version, updated_at FROM dataset_version`).Scan(&v.Version, &v.UpdatedAt)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperror.NotFound("Dataset version not found")
+	}
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return &v, nil
+}
+
+// simplifyExpr menghasilkan ekspresi SQL geometry yang disederhanakan dengan tolerance
+// (derajat) dari parameter ke-n. Penyederhanaan memakai snapping ke grid sehingga setiap
+// vertex dipetakan secara deterministik: perbatasan yang dipakai bersama dua wilayah tetap
+// identik walaupun keduanya diambil pada request terpisah. Tolerance 0 berarti geometry asli.
+func simplifyExpr(column string, n int) string {
+	param := fmt.Sprintf("$%d::float8", n)
+	return "CASE WHEN " + param + " > 0 THEN ST_CollectionExtract(ST_MakeValid(ST_SnapToGrid(" +
+		column + ", " + param + ")), 3) ELSE " + column + " END"
+}
+
+// MaxSimplifyZoom adalah zoom level tertinggi yang tolerance-nya dipakai ZoomTolerance dan QuantizeTolerance
+const MaxSimplifyZoom = 22
+
+// ZoomTolerance mengembalikan lebar satu pixel tile 256px dalam derajat pada zoom z
+func ZoomTolerance(z int) float64 {
+	return 360.0 / (256.0 * math.Exp2(float64(z)))
+}
+
+// QuantizeTolerance membulatkan tolerance ke bawah ke ZoomTolerance terdekat (z 0-22).
+// Semua endpoint memakai pembulatan yang sama sehingga wilayah yang diambil lewat endpoint
+// berbeda tetap di-snap ke grid yang sama, dan cache GeoJSON paling banyak menyimpan 24
+// tolerance per wilayah. Pembulatan ke bawah berarti geometry tidak pernah lebih kasar dari
+// yang diminta; tolerance di bawah zoom 22 menjadi 0 (geometry asli).
+func QuantizeTolerance(tolerance float64) float64 {
+	if tolerance <= 0 {
+		return 0
+	}
+	for z := 0; z <= MaxSimplifyZoom; z++ {
+		if step := ZoomTolerance(z); step <= tolerance {
+			return step
+		}
+	}
+	return 0
+}
+
+// newFeature melengkapi feature hasil scan dengan type dan geometry.
+// Geometry dari ST_AsGeoJSON dipakai apa adanya tanpa di-decode.
+func newFeature(feature models.GeoJSONFeature, featureType string, geometry []byte) *models.GeoJSONFeature {
+	feature.Type = "Feature"
+	feature.Properties.Type = featureType
+	feature.Geometry = geometry
+	return &feature
 }
@@ -0,0 +1,597 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"location-svc/internal/apperror"
+	"location-svc/internal/models"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MemoryRegion adalah satu wilayah dalam MemoryStore. Geometry disederhanakan
+// menjadi bounding box [minLon, minLat, maxLon, maxLat].
+type MemoryRegion struct {
+	Code       string
+	Name       string
+	ParentCode string
+	BBox       [4]float64
+}
+
+// MemoryFixtures berisi data awal MemoryStore untuk keempat level
+type MemoryFixtures struct {
+	Propinsi  []MemoryRegion
+	Kabupaten []MemoryRegion
+	Kecamatan []MemoryRegion
+	Kelurahan []MemoryRegion
+}
+
+// SampleFixtures mengembalikan data yang setara dengan database/sample_data.sql
+func SampleFixtures() MemoryFixtures {
+	return MemoryFixtures{
+		Propinsi: []MemoryRegion{
+			{Code: "32", Name: "Jawa Barat", BBox: [4]float64{106.0, -8.0, 109.0, -6.0}},
+			{Code: "31", Name: "DKI Jakarta", BBox: [4]float64{106.7, -6.3, 106.9, -6.1}},
+			{Code: "33", Name: "Jawa Tengah", BBox: [4]float64{109.0, -8.0, 112.0, -6.0}},
+		},
+		Kabupaten: []MemoryRegion{
+			{Code: "3201", Name: "Kabupaten Bogor", ParentCode: "32", BBox: [4]float64{106.5, -6.8, 107.0, -6.3}},
+			{Code: "3202", Name: "Kabupaten Sukabumi", ParentCode: "32", BBox: [4]float64{106.8, -7.3, 107.3, -6.8}},
+			{Code: "3171", Name: "Kota Jakarta Selatan", ParentCode: "31", BBox: [4]float64{106.7, -6.35, 106.85, -6.2}},
+			{Code: "3172", Name: "Kota Jakarta Timur", ParentCode: "31", BBox: [4]float64{106.85, -6.25, 106.95, -6.1}},
+		},
+		Kecamatan: []MemoryRegion{
+			{Code: "3201010", Name: "Nanggung", ParentCode: "3201", BBox: [4]float64{106.5, -6.4, 106.6, -6.3}},
+			{Code: "3201020", Name: "Leuwiliang", ParentCode: "3201", BBox: [4]float64{106.6, -6.5, 106.7, -6.4}},
+			{Code: "3202010", Name: "Pelabuhan Ratu", ParentCode: "3202", BBox: [4]float64{106.8, -6.9, 106.9, -6.8}},
+			{Code: "3171010", Name: "Jagakarsa", ParentCode: "3171", BBox: [4]float64{106.7, -6.25, 106.75, -6.2}},
+		},
+		Kelurahan: []MemoryRegion{
+			{Code: "3201010001", Name: "Nanggung", ParentCode: "3201010", BBox: [4]float64{106.5, -6.35, 106.55, -6.3}},
+			{Code: "3201010002", Name: "Kalibunder", ParentCode: "3201010", BBox: [4]float64{106.55, -6.4, 106.6, -6.35}},
+			{Code: "3201020001", Name: "Leuwiliang", ParentCode: "3201020", BBox: [4]float64{106.6, -6.45, 106.65, -6.4}},
+			{Code: "3171010001", Name: "Jagakarsa", ParentCode: "3171010", BBox: [4]float64{106.7, -6.23, 106.73, -6.2}},
+		},
+	}
+}
+
+// MemoryStore adalah implementasi LocationStore in-memory tanpa database,
+// ditujukan untuk testing handler. Perilakunya mengikuti LocationRepository.
+type MemoryStore struct {
+	propinsi  map[string]MemoryRegion
+	kabupaten map[string]MemoryRegion
+	kecamatan map[string]MemoryRegion
+	kelurahan map[string]MemoryRegion
+	version   models.DatasetVersion
+}
+
+// NewMemoryStore creates new instance of MemoryStore dari fixtures
+func NewMemoryStore(fixtures MemoryFixtures) *MemoryStore {
+	index := func(regions []MemoryRegion) map[string]MemoryRegion {
+		m := make(map[string]MemoryRegion, len(regions))
+		for _, r := range regions {
+			m[r.Code] = r
+		}
+		return m
+	}
+
+	return &MemoryStore{
+		propinsi:  index(fixtures.Propinsi),
+		kabupaten: index(fixtures.Kabupaten),
+		kecamatan: index(fixtures.Kecamatan),
+		kelurahan: index(fixtures.Kelurahan),
+		// Presisi detik seperti header Last-Modified
+		version: models.DatasetVersion{Version: 1, UpdatedAt: time.Now().UTC().Truncate(time.Second)},
+	}
+}
+
+// GetPropinsi mendapatkan semua propinsi
+func (s *MemoryStore) GetPropinsi(ctx context.Context, page models.PageParams) (*models.Page[models.Propinsi], error) {
+	return s.SearchPropinsiByName(ctx, "", page)
+}
+
+// SearchPropinsiByName mencari propinsi berdasarkan nama
+func (s *MemoryStore) SearchPropinsiByName(ctx context.Context, name string, page models.PageParams) (*models.Page[models.Propinsi], error) {
+	var provinces []models.Propinsi
+	for _, p := range sortedByName(s.propinsi) {
+		provinces = append(provinces, models.Propinsi{KdPropinsi: p.Code, NmPropinsi: p.Name})
+	}
+
+	return memoryPage(provinces, name, page, func(p *models.Propinsi) (string, string, *float64) {
+		return p.NmPropinsi, p.KdPropinsi, &p.Score
+	})
+}
+
+// GetKabupaten mendapatkan kabupaten berdasarkan propinsi_id
+func (s *MemoryStore) GetKabupaten(ctx context.Context, propinsiID string, page models.PageParams) (*models.Page[models.Kabupaten], error) {
+	return s.SearchKabupatenByName(ctx, "", &propinsiID, page)
+}
+
+// SearchKabupatenByName mencari kabupaten berdasarkan nama dengan filter propinsi
+func (s *MemoryStore) SearchKabupatenByName(ctx context.Context, name string, propinsiID *string, page models.PageParams) (*models.Page[models.Kabupaten], error) {
+	var kabupatens []models.Kabupaten
+	for _, r := range sortedByName(s.kabupaten) {
+		k := s.kabupatenModel(r)
+		if matches(propinsiID, k.KdPropinsi) {
+			kabupatens = append(kabupatens, k)
+		}
+	}
+
+	return memoryPage(kabupatens, name, page, func(k *models.Kabupaten) (string, string, *float64) {
+		return k.NmKabupaten, k.KdKabupaten, &k.Score
+	})
+}
+
+// GetKecamatan mendapatkan kecamatan berdasarkan propinsi_id dan kabupaten_id
+func (s *MemoryStore) GetKecamatan(ctx context.Context, propinsiID, kabupatenID string, page models.PageParams) (*models.Page[models.Kecamatan], error) {
+	return s.SearchKecamatanByName(ctx, "", &propinsiID, &kabupatenID, page)
+}
+
+// SearchKecamatanByName mencari kecamatan berdasarkan nama dengan filter hierarki
+func (s *MemoryStore) SearchKecamatanByName(ctx context.Context, name string, propinsiID, kabupatenID *string, page models.PageParams) (*models.Page[models.Kecamatan], error) {
+	var kecamatans []models.Kecamatan
+	for _, r := range sortedByName(s.kecamatan) {
+		kec := s.kecamatanModel(r)
+		if matches(propinsiID, kec.KdPropinsi) && matches(kabupatenID, kec.KdKabupaten) {
+			kecamatans = append(kecamatans, kec)
+		}
+	}
+
+	return memoryPage(kecamatans, name, page, func(kec *models.Kecamatan) (string, string, *float64) {
+		return kec.NmKecamatan, kec.KdKecamatan, &kec.Score
+	})
+}
+
+// GetKelurahan mendapatkan kelurahan berdasarkan hierarki lengkap
+func (s *MemoryStore) GetKelurahan(ctx context.Context, propinsiID, kabupatenID, kecamatanID string, page models.PageParams) (*models.Page[models.Kelurahan], error) {
+	return s.SearchKelurahanByName(ctx, "", &propinsiID, &kabupatenID, &kecamatanID, page)
+}
+
+// SearchKelurahanByName mencari kelurahan berdasarkan nama dengan filter hierarki
+func (s *MemoryStore) SearchKelurahanByName(ctx context.Context, name string, propinsiID, kabupatenID, kecamatanID *string, page models.PageParams) (*models.Page[models.Kelurahan], error) {
+	var kelurahans []models.Kelurahan
+	for _, r := range sortedByName(s.kelurahan) {
+		kel := s.kelurahanModel(r)
+		if matches(propinsiID, kel.KdPropinsi) && matches(kabupatenID, kel.KdKabupaten) && matches(kecamatanID, kel.KdKecamatan) {
+			kelurahans = append(kelurahans, kel)
+		}
+	}
+
+	return memoryPage(kelurahans, name, page, func(kel *models.Kelurahan) (string, string, *float64) {
+		return kel.NmKelurahan, kel.KdKelurahan, &kel.Score
+	})
+}
+
+// Search mencari nama wilayah di semua level sekaligus dengan urutan yang sama seperti LocationRepository
+func (s *MemoryStore) Search(ctx context.Context, q string, limit int) ([]models.SearchResult, error) {
+	var results []models.SearchResult
+	var keys [][]interface{}
+
+	add := func(depth int, level, code string, names ...string) {
+		rank, score, ok := fuzzyRank(names[0], q)
+		if !ok {
+			return
+		}
+
+		results = append(results, models.SearchResult{
+			Level:      level,
+			Code:       code,
+			Name:       names[0],
+			Breadcrumb: strings.Join(names, ", "),
+			Score:      score,
+		})
+		keys = append(keys, []interface{}{rank, -score, float64(depth), names[0], code})
+	}
+
+	for _, r := range sortedByCode(s.propinsi) {
+		add(0, "propinsi", r.Code, r.Name)
+	}
+	for _, r := range sortedByCode(s.kabupaten) {
+		k := s.kabupatenModel(r)
+		add(1, "kabupaten", k.KdKabupaten, k.NmKabupaten, k.NmPropinsi)
+	}
+	for _, r := range sortedByCode(s.kecamatan) {
+		kec := s.kecamatanModel(r)
+		add(2, "kecamatan", kec.KdKecamatan, kec.NmKecamatan, kec.NmKabupaten, kec.NmPropinsi)
+	}
+	for _, r := range sortedByCode(s.kelurahan) {
+		kel := s.kelurahanModel(r)
+		add(3, "kelurahan", kel.KdKelurahan, kel.NmKelurahan, kel.NmKecamatan, kel.NmKabupaten, kel.NmPropinsi)
+	}
+
+	order := make([]int, len(results))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return compareKeys(keys[order[i]], keys[order[j]]) < 0 })
+
+	sorted := []models.SearchResult{}
+	for _, idx := range order[:min(limit, len(order))] {
+		sorted = append(sorted, results[idx])
+	}
+
+	return sorted, nil
+}
+
+// GetPropinsiGeoJSON mendapatkan GeoJSON propinsi berdasarkan ID
+func (s *MemoryStore) GetPropinsiGeoJSON(ctx context.Context, id string, tolerance float64) (*models.GeoJSONFeature, error) {
+	return regionFeature(s.propinsi, id, "propinsi", "Province not found")
+}
+
+// GetKabupatenGeoJSON mendapatkan GeoJSON kabupaten berdasarkan ID
+func (s *MemoryStore) GetKabupatenGeoJSON(ctx context.Context, id string, tolerance float64) (*models.GeoJSONFeature, error) {
+	return regionFeature(s.kabupaten, id, "kabupaten", "Regency not found")
+}
+
+// GetKecamatanGeoJSON mendapatkan GeoJSON kecamatan berdasarkan ID
+func (s *MemoryStore) GetKecamatanGeoJSON(ctx context.Context, id string, tolerance float64) (*models.GeoJSONFeature, error) {
+	return regionFeature(s.kecamatan, id, "kecamatan", "District not found")
+}
+
+// GetKelurahanGeoJSON mendapatkan GeoJSON kelurahan berdasarkan ID
+func (s *MemoryStore) GetKelurahanGeoJSON(ctx context.Context, id string, tolerance float64) (*models.GeoJSONFeature, error) {
+	return regionFeature(s.kelurahan, id, "kelurahan", "Village not found")
+}
+
+// GetKabupatenFeatureCollection memanggil fn untuk GeoJSON setiap kabupaten dalam satu propinsi
+func (s *MemoryStore) GetKabupatenFeatureCollection(ctx context.Context, propinsiID string, tolerance float64, fn func(*models.GeoJSONFeature) error) error {
+	if _, ok := s.propinsi[propinsiID]; !ok {
+		return apperror.NotFound("Province not found")
+	}
+
+	for _, r := range sortedByCode(s.kabupaten) {
+		if r.ParentCode != propinsiID {
+			continue
+		}
+
+		k := s.kabupatenModel(r)
+		feature := bboxFeature(r, "kabupaten")
+		feature.Properties.KdPropinsi, feature.Properties.NmPropinsi = k.KdPropinsi, k.NmPropinsi
+		if err := fn(&feature); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetKecamatanFeatureCollection memanggil fn untuk GeoJSON setiap kecamatan dalam satu kabupaten
+func (s *MemoryStore) GetKecamatanFeatureCollection(ctx context.Context, kabupatenID string, tolerance float64, fn func(*models.GeoJSONFeature) error) error {
+	if _, ok := s.kabupaten[kabupatenID]; !ok {
+		return apperror.NotFound("Regency not found")
+	}
+
+	for _, r := range sortedByCode(s.kecamatan) {
+		if r.ParentCode != kabupatenID {
+			continue
+		}
+
+		kec := s.kecamatanModel(r)
+		feature := bboxFeature(r, "kecamatan")
+		feature.Properties.KdPropinsi, feature.Properties.NmPropinsi = kec.KdPropinsi, kec.NmPropinsi
+		feature.Properties.KdKabupaten, feature.Properties.NmKabupaten = kec.KdKabupaten, kec.NmKabupaten
+		if err := fn(&feature); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetKelurahanFeatureCollection memanggil fn untuk GeoJSON setiap kelurahan dalam satu kecamatan
+func (s *MemoryStore) GetKelurahanFeatureCollection(ctx context.Context, kecamatanID string, tolerance float64, fn func(*models.GeoJSONFeature) error) error {
+	if _, ok := s.kecamatan[kecamatanID]; !ok {
+		return apperror.NotFound("District not found")
+	}
+
+	for _, r := range sortedByCode(s.kelurahan) {
+		if r.ParentCode != kecamatanID {
+			continue
+		}
+
+		kel := s.kelurahanModel(r)
+		feature := bboxFeature(r, "kelurahan")
+		feature.Properties.KdPropinsi, feature.Properties.NmPropinsi = kel.KdPropinsi, kel.NmPropinsi
+		feature.Properties.KdKabupaten, feature.Properties.NmKabupaten = kel.KdKabupaten, kel.NmKabupaten
+		feature.Properties.KdKecamatan, feature.Properties.NmKecamatan = kel.KdKecamatan, kel.NmKecamatan
+		if err := fn(&feature); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportLevel memanggil fn untuk setiap wilayah satu level dengan geometri bounding box.
+// Seperti LocationRepository, export berhenti dengan error timeout jika ctx selesai.
+func (s *MemoryStore) ExportLevel(ctx context.Context, level, parentID string, tolerance float64, encoding GeometryEncoding,
+	fn func(models.ExportRow) error) error {
+	if _, err := validateExport(level, parentID); err != nil {
+		return err
+	}
+
+	regions := map[string]map[string]MemoryRegion{
+		"propinsi":  s.propinsi,
+		"kabupaten": s.kabupaten,
+		"kecamatan": s.kecamatan,
+		"kelurahan": s.kelurahan,
+	}[level]
+
+	for _, r := range sortedByCode(regions) {
+		if err := ctx.Err(); err != nil {
+			return dbError(err)
+		}
+		if parentID != "" && r.ParentCode != parentID {
+			continue
+		}
+
+		var hierarchy []string
+		switch level {
+		case "propinsi":
+			hierarchy = []string{r.Code, r.Name}
+		case "kabupaten":
+			k := s.kabupatenModel(r)
+			hierarchy = []string{k.KdPropinsi, k.NmPropinsi, k.KdKabupaten, k.NmKabupaten}
+		case "kecamatan":
+			kec := s.kecamatanModel(r)
+			hierarchy = []string{kec.KdPropinsi, kec.NmPropinsi, kec.KdKabupaten, kec.NmKabupaten, kec.KdKecamatan, kec.NmKecamatan}
+		case "kelurahan":
+			kel := s.kelurahanModel(r)
+			hierarchy = []string{kel.KdPropinsi, kel.NmPropinsi, kel.KdKabupaten, kel.NmKabupaten,
+				kel.KdKecamatan, kel.NmKecamatan, kel.KdKelurahan, kel.NmKelurahan}
+		}
+
+		geometry := bboxGeoJSON(r.BBox)
+		if encoding == GeometryWKT {
+			geometry = bboxWKT(r.BBox)
+		}
+
+		row, err := exportRow(level, hierarchy, geometry)
+		if err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReverseGeocode mendapatkan hierarki kelurahan yang memuat titik koordinat (lat, lon),
+// dengan aturan perbatasan yang sama seperti LocationRepository.ReverseGeocode
+func (s *MemoryStore) ReverseGeocode(ctx context.Context, lat, lon float64) (*models.Kelurahan, error) {
+	var found *MemoryRegion
+	foundInside := false
+
+	for _, r := range sortedByCode(s.kelurahan) {
+		b := r.BBox
+		if lon < b[0] || lon > b[2] || lat < b[1] || lat > b[3] {
+			continue
+		}
+
+		inside := lon > b[0] && lon < b[2] && lat > b[1] && lat < b[3]
+		if found == nil || (inside && !foundInside) {
+			region := r
+			found, foundInside = &region, inside
+		}
+	}
+
+	if found == nil {
+		return nil, apperror.NotFound("No region found at the given coordinate")
+	}
+
+	kel := s.kelurahanModel(*found)
+	return &kel, nil
+}
+
+// BatchReverseGeocode mendapatkan hierarki kelurahan untuk sekumpulan titik
+func (s *MemoryStore) BatchReverseGeocode(ctx context.Context, lats, lons []float64) ([]*models.Kelurahan, error) {
+	if len(lats) != len(lons) {
+		return nil, apperror.InvalidArgument(fmt.Sprintf("lats and lons length mismatch: %d != %d", len(lats), len(lons)))
+	}
+
+	results := make([]*models.Kelurahan, len(lats))
+	for i := range lats {
+		if kel, err := s.ReverseGeocode(ctx, lats[i], lons[i]); err == nil {
+			results[i] = kel
+		}
+	}
+	return results, nil
+}
+
+// DatasetVersion mengembalikan versi 1 dengan waktu pembuatan store
+func (s *MemoryStore) DatasetVersion(ctx context.Context) (*models.DatasetVersion, error) {
+	v := s.version
+	return &v, nil
+}
+
+// GetTile selalu mengembalikan tile kosong karena MemoryStore tidak bisa membuat MVT
+func (s *MemoryStore) GetTile(ctx context.Context, level string, z, x, y int) ([]byte, error) {
+	if _, ok := tileSources[level]; !ok && level != TileLevelAuto {
+		return nil, apperror.InvalidArgument(fmt.Sprintf("Unknown tile level: %s", level))
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) kabupatenModel(r MemoryRegion) models.Kabupaten {
+	p := s.propinsi[r.ParentCode]
+	return models.Kabupaten{
+		KdPropinsi:  p.Code,
+		NmPropinsi:  p.Name,
+		KdKabupaten: r.Code,
+		NmKabupaten: r.Name,
+	}
+}
+
+func (s *MemoryStore) kecamatanModel(r MemoryRegion) models.Kecamatan {
+	k := s.kabupatenModel(s.kabupaten[r.ParentCode])
+	return models.Kecamatan{
+		KdPropinsi:  k.KdPropinsi,
+		NmPropinsi:  k.NmPropinsi,
+		KdKabupaten: k.KdKabupaten,
+		NmKabupaten: k.NmKabupaten,
+		KdKecamatan: r.Code,
+		NmKecamatan: r.Name,
+	}
+}
+
+func (s *MemoryStore) kelurahanModel(r MemoryRegion) models.Kelurahan {
+	kec := s.kecamatanModel(s.kecamatan[r.ParentCode])
+	return models.Kelurahan{
+		KdPropinsi:  kec.KdPropinsi,
+		NmPropinsi:  kec.NmPropinsi,
+		KdKabupaten: kec.KdKabupaten,
+		NmKabupaten: kec.NmKabupaten,
+		KdKecamatan: kec.KdKecamatan,
+		NmKecamatan: kec.NmKecamatan,
+		KdKelurahan: r.Code,
+		NmKelurahan: r.Name,
+	}
+}
+
+// regionFeature mendapatkan feature satu wilayah berdasarkan kode
+func regionFeature(regions map[string]MemoryRegion, id, featureType, notFound string) (*models.GeoJSONFeature, error) {
+	r, ok := regions[id]
+	if !ok {
+		return nil, apperror.NotFound(notFound)
+	}
+
+	feature := bboxFeature(r, featureType)
+	return &feature, nil
+}
+
+// bboxFeature membuat GeoJSON Feature dengan geometry MultiPolygon dari bounding box wilayah
+func bboxFeature(r MemoryRegion, featureType string) models.GeoJSONFeature {
+	id, _ := strconv.Atoi(r.Code)
+	return models.GeoJSONFeature{
+		Type:       "Feature",
+		Properties: models.GeoJSONProperties{ID: id, Name: r.Name, Type: featureType},
+		Geometry:   json.RawMessage(bboxGeoJSON(r.BBox)),
+	}
+}
+
+// bboxGeoJSON mengembalikan bounding box sebagai GeoJSON MultiPolygon
+func bboxGeoJSON(b [4]float64) string {
+	return fmt.Sprintf(`{"type":"MultiPolygon","coordinates":[[[[%[1]g,%[4]g],[%[3]g,%[4]g],[%[3]g,%[2]g],[%[1]g,%[2]g],[%[1]g,%[4]g]]]]}`, b[0], b[1], b[2], b[3])
+}
+
+// bboxWKT mengembalikan bounding box sebagai WKT MULTIPOLYGON dengan ring yang sama seperti bboxGeoJSON
+func bboxWKT(b [4]float64) string {
+	return fmt.Sprintf("MULTIPOLYGON(((%[1]g %[4]g,%[3]g %[4]g,%[3]g %[2]g,%[1]g %[2]g,%[1]g %[4]g)))", b[0], b[1], b[2], b[3])
+}
+
+func sortedByName(regions map[string]MemoryRegion) []MemoryRegion {
+	sorted := sortedByCode(regions)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
+
+func sortedByCode(regions map[string]MemoryRegion) []MemoryRegion {
+	sorted := make([]MemoryRegion, 0, len(regions))
+	for _, r := range regions {
+		sorted = append(sorted, r)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Code < sorted[j].Code })
+	return sorted
+}
+
+// memoryPage menyaring items (terurut berdasarkan nama) dengan pencarian nama lalu
+// mengurutkan dan memaginasi dengan aturan yang sama seperti LocationRepository.
+// fields mengembalikan nama, kode dan pointer ke field Score sebuah item.
+func memoryPage[T any](items []T, query string, page models.PageParams, fields func(*T) (string, string, *float64)) (*models.Page[T], error) {
+	matched := []T{}
+	var keys [][]interface{}
+
+	for _, item := range items {
+		name, code, score := fields(&item)
+		if query == "" {
+			matched = append(matched, item)
+			keys = append(keys, []interface{}{name, code})
+			continue
+		}
+
+		rank, similarity, ok := fuzzyRank(name, query)
+		if !ok {
+			continue
+		}
+
+		*score = similarity
+		matched = append(matched, item)
+		keys = append(keys, []interface{}{rank, -similarity, name, code})
+	}
+
+	order := make([]int, len(matched))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return compareKeys(keys[order[i]], keys[order[j]]) < 0 })
+
+	sortedItems := make([]T, len(order))
+	sortedKeys := make([][]interface{}, len(order))
+	for i, idx := range order {
+		sortedItems[i], sortedKeys[i] = matched[idx], keys[idx]
+	}
+
+	return paginateSlice(sortedItems, sortedKeys, page)
+}
+
+// fuzzyRank meniru fuzzyCond dan pageQuery.orderBy: mengembalikan kelompok relevansi
+// (0 persis, 1 prefix, 2 awal kata, 3 substring, 4 fuzzy) dan skor kemiripan
+func fuzzyRank(name, query string) (float64, float64, bool) {
+	n, q := strings.ToLower(name), strings.ToLower(query)
+	similarity := wordSimilarity(q, n)
+
+	switch {
+	case n == q:
+		return 0, similarity, true
+	case strings.HasPrefix(n, q):
+		return 1, similarity, true
+	case strings.Contains(n, " "+q):
+		return 2, similarity, true
+	case strings.Contains(n, q):
+		return 3, similarity, true
+	case similarity >= fuzzyThreshold:
+		return 4, similarity, true
+	default:
+		return 0, 0, false
+	}
+}
+
+// wordSimilarity mendekati word_similarity pg_trgm: proporsi trigram query yang juga ada di name
+func wordSimilarity(query, name string) float64 {
+	q, n := trigrams(query), trigrams(name)
+	if len(q) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for t := range q {
+		if n[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(q))
+}
+
+// trigrams menghasilkan trigram per kata seperti pg_trgm (dua spasi di depan, satu di belakang)
+func trigrams(s string) map[string]bool {
+	set := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, w := range words {
+		padded := []rune("  " + w + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// matches mengecek filter opsional; filter nil selalu cocok
+func matches(filter *string, value string) bool {
+	return filter == nil || *filter == value
+}
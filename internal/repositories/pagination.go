@@ -0,0 +1,275 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"location-svc/internal/apperror"
+	"location-svc/internal/models"
+	"strings"
+)
+
+// queryer adalah subset *sql.DB dan *sql.Tx yang dipakai queryPage dan operasi LocationRepository.read
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// pageQuery mendeskripsikan query search yang dipaginasi. Tanpa pencarian nama hasil
+// diurutkan berdasarkan (nameCol, codeCol); dengan pencarian nama hasil diurutkan
+// berdasarkan relevansi terlebih dahulu (lihat orderBy).
+type pageQuery struct {
+	columns string
+	from    string
+	nameCol string
+	codeCol string
+}
+
+var (
+	propinsiSearch = pageQuery{
+		columns: "kd_propinsi, nm_propinsi",
+		from:    "FROM propinsi",
+		nameCol: "nm_propinsi",
+		codeCol: "kd_propinsi",
+	}
+
+	kabupatenSearch = pageQuery{
+		columns: "p.kd_propinsi, p.nm_propinsi, k.kd_kabupaten, k.nm_kabupaten",
+		from: `FROM kabupaten k
+			JOIN propinsi p ON k.kd_propinsi = p.kd_propinsi`,
+		nameCol: "k.nm_kabupaten",
+		codeCol: "k.kd_kabupaten",
+	}
+
+	kecamatanSearch = pageQuery{
+		columns: "p.kd_propinsi, p.nm_propinsi, k.kd_kabupaten, k.nm_kabupaten, kec.kd_kecamatan, kec.nm_kecamatan",
+		from: `FROM kecamatan kec
+			JOIN kabupaten k ON kec.kd_kabupaten = k.kd_kabupaten
+			JOIN propinsi p ON k.kd_propinsi = p.kd_propinsi`,
+		nameCol: "kec.nm_kecamatan",
+		codeCol: "kec.kd_kecamatan",
+	}
+
+	kelurahanSearch = pageQuery{
+		columns: `p.kd_propinsi, p.nm_propinsi, k.kd_kabupaten, k.nm_kabupaten,
+			kec.kd_kecamatan, kec.nm_kecamatan, kel.kd_kelurahan, kel.nm_kelurahan`,
+		from: `FROM kelurahan kel
+			JOIN kecamatan kec ON kel.kd_kecamatan = kec.kd_kecamatan
+			JOIN kabupaten k ON kec.kd_kabupaten = k.kd_kabupaten
+			JOIN propinsi p ON k.kd_propinsi = p.kd_propinsi`,
+		nameCol: "kel.nm_kelurahan",
+		codeCol: "kel.kd_kelurahan",
+	}
+)
+
+// scoreExpr mengembalikan ekspresi skor kemiripan nama terhadap $1, atau 0 tanpa pencarian nama
+func (q pageQuery) scoreExpr(ranked bool) string {
+	if !ranked {
+		return "0::float8"
+	}
+	return "word_similarity($1, " + q.nameCol + ")::float8"
+}
+
+// rankExpr mengembalikan kelompok relevansi nama terhadap $1: 0 persis, 1 prefix,
+// 2 awal kata, 3 substring, 4 kecocokan fuzzy
+func (q pageQuery) rankExpr() string {
+	return `CASE
+			WHEN lower(` + q.nameCol + `) = lower($1) THEN 0
+			WHEN ` + q.nameCol + ` ILIKE $1 || '%' THEN 1
+			WHEN ` + q.nameCol + ` ILIKE '% ' || $1 || '%' THEN 2
+			WHEN ` + q.nameCol + ` ILIKE '%' || $1 || '%' THEN 3
+			ELSE 4
+		END`
+}
+
+// orderBy mengembalikan ekspresi urutan hasil. Dengan pencarian nama ($1) hasil diurutkan
+// berdasarkan rankExpr lalu skor di dalam setiap kelompok. Semua ekspresi ascending agar bisa
+// dipakai sebagai keyset dengan perbandingan row.
+func (q pageQuery) orderBy(ranked bool) []string {
+	if !ranked {
+		return []string{q.nameCol, q.codeCol}
+	}
+
+	return []string{q.rankExpr(), "-" + q.scoreExpr(true), q.nameCol, q.codeCol}
+}
+
+// searchFilter menyusun klausa WHERE; setiap "?" diganti parameter bernomor ($1, $2, ...)
+type searchFilter struct {
+	conds []string
+	args  []interface{}
+}
+
+func (f *searchFilter) add(cond string, args ...interface{}) {
+	for _, arg := range args {
+		f.args = append(f.args, arg)
+		cond = strings.Replace(cond, "?", fmt.Sprintf("$%d", len(f.args)), 1)
+	}
+	f.conds = append(f.conds, cond)
+}
+
+func (f *searchFilter) where() string {
+	if len(f.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(f.conds, " AND ")
+}
+
+// queryPage menjalankan query search dengan total count, keyset cursor atau offset, dan limit.
+// Jika ranked, filter pertama harus berupa pencarian nama sehingga nama berada di $1.
+// scan harus men-scan kolom q.columns, skor, lalu keys (nilai keyset) secara berurutan.
+func queryPage[T any](ctx context.Context, db queryer, q pageQuery, filter searchFilter, page models.PageParams, ranked bool,
+	scan func(rows *sql.Rows, keys ...interface{}) (T, error)) (*models.Page[T], error) {
+	var total int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) "+q.from+filter.where(), filter.args...).Scan(&total); err != nil {
+		return nil, dbError(err)
+	}
+
+	orderBy := q.orderBy(ranked)
+
+	if page.Cursor != "" {
+		after, err := decodeCursor(page.Cursor, len(orderBy))
+		if err != nil {
+			return nil, err
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(after)), ", ")
+		filter.add("("+strings.Join(orderBy, ", ")+") > ("+placeholders+")", after...)
+	}
+
+	// Ambil satu baris lebih untuk mengetahui apakah masih ada halaman berikutnya
+	query := "SELECT " + q.columns + ", " + q.scoreExpr(ranked) + ", " + strings.Join(orderBy, ", ") +
+		" " + q.from + filter.where() +
+		" ORDER BY " + strings.Join(orderBy, ", ") +
+		fmt.Sprintf(" LIMIT %d OFFSET %d", page.Limit+1, page.Offset)
+
+	rows, err := db.QueryContext(ctx, query, filter.args...)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer rows.Close()
+
+	items := []T{}
+	var lastKeys []interface{}
+	for rows.Next() {
+		keys := make([]interface{}, len(orderBy))
+		dest := make([]interface{}, len(orderBy))
+		for i := range keys {
+			dest[i] = &keys[i]
+		}
+
+		item, err := scan(rows, dest...)
+		if err != nil {
+			return nil, dbError(err)
+		}
+		items = append(items, item)
+
+		if len(items) == page.Limit {
+			lastKeys = keys
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(err)
+	}
+
+	return newPage(items, total, page, lastKeys), nil
+}
+
+// paginateSlice menerapkan cursor, offset dan limit pada items yang sudah terurut
+// berdasarkan keys (nilai keyset per item), dengan semantik yang sama seperti queryPage.
+func paginateSlice[T any](items []T, keys [][]interface{}, page models.PageParams) (*models.Page[T], error) {
+	total := len(items)
+	start := 0
+
+	if page.Cursor != "" && len(items) > 0 {
+		after, err := decodeCursor(page.Cursor, len(keys[0]))
+		if err != nil {
+			return nil, err
+		}
+
+		for start < len(items) && compareKeys(keys[start], after) <= 0 {
+			start++
+		}
+	}
+
+	start = min(start+page.Offset, len(items))
+	end := min(start+page.Limit+1, len(items))
+
+	var lastKeys []interface{}
+	if end-start > page.Limit {
+		lastKeys = keys[start+page.Limit-1]
+	}
+
+	return newPage(append([]T{}, items[start:end]...), total, page, lastKeys), nil
+}
+
+// newPage menyusun envelope dari items yang berisi paling banyak page.Limit+1 baris
+func newPage[T any](items []T, total int, page models.PageParams, lastKeys []interface{}) *models.Page[T] {
+	result := &models.Page[T]{
+		Data:   items,
+		Total:  total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+
+	if len(items) > page.Limit {
+		result.Data = items[:page.Limit]
+		result.NextCursor = encodeCursor(lastKeys)
+	}
+
+	return result
+}
+
+// encodeCursor menyandikan nilai keyset baris terakhir menjadi string opaque
+func encodeCursor(keys []interface{}) string {
+	b, _ := json.Marshal(keys)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor membaca cursor yang dibuat encodeCursor dan memastikan jumlah nilainya n
+func decodeCursor(cursor string, n int) ([]interface{}, error) {
+	var keys []interface{}
+
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(b, &keys)
+	}
+	if err != nil || len(keys) != n {
+		return nil, apperror.InvalidArgument("Invalid cursor")
+	}
+
+	for _, k := range keys {
+		switch k.(type) {
+		case string, float64:
+		default:
+			return nil, apperror.InvalidArgument("Invalid cursor")
+		}
+	}
+
+	return keys, nil
+}
+
+// compareKeys membandingkan dua keyset (string atau angka) secara leksikografis
+func compareKeys(a, b []interface{}) int {
+	for i := range a {
+		var c int
+		switch av := a[i].(type) {
+		case string:
+			bv, _ := b[i].(string)
+			c = strings.Compare(av, bv)
+		case float64:
+			bv, _ := b[i].(float64)
+			switch {
+			case av < bv:
+				c = -1
+			case av > bv:
+				c = 1
+			}
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"location-svc/internal/logging"
+	"strings"
+	"time"
+)
+
+// maxLoggedArg adalah panjang maksimum satu parameter query di log, mis. array koordinat batch reverse
+const maxLoggedArg = 200
+
+// SetSlowQueryLog mencatat query yang berjalan lebih lama dari threshold beserta parameternya
+// ke logger request (logging.FromContext). Threshold 0 menonaktifkan log query lambat.
+func (r *LocationRepository) SetSlowQueryLog(threshold time.Duration) {
+	r.slowQuery = threshold
+}
+
+// conn mengembalikan q (pool dari r.reader atau transaksi) yang dibungkus pencatat query lambat jika aktif
+func (r *LocationRepository) conn(q queryer) queryer {
+	if r.slowQuery <= 0 {
+		return q
+	}
+	return &slowQueryLog{q: q, threshold: r.slowQuery}
+}
+
+// slowQueryLog membungkus queryer dan mencatat query lambat. Untuk Query durasi diukur
+// sampai baris pertama siap dibaca, tidak termasuk waktu membaca semua baris.
+type slowQueryLog struct {
+	q         queryer
+	threshold time.Duration
+}
+
+func (l *slowQueryLog) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	defer l.observe(ctx, time.Now(), query, args)
+	return l.q.QueryContext(ctx, query, args...)
+}
+
+func (l *slowQueryLog) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	defer l.observe(ctx, time.Now(), query, args)
+	return l.q.QueryRowContext(ctx, query, args...)
+}
+
+func (l *slowQueryLog) observe(ctx context.Context, start time.Time, query string, args []interface{}) {
+	elapsed := time.Since(start)
+	if elapsed < l.threshold {
+		return
+	}
+
+	params := make([]string, len(args))
+	for i, arg := range args {
+		params[i] = fmt.Sprintf("%v", arg)
+		if len(params[i]) > maxLoggedArg {
+			params[i] = params[i][:maxLoggedArg] + "..."
+		}
+	}
+
+	logging.FromContext(ctx).Warn("slow query",
+		"duration_ms", float64(elapsed.Microseconds())/1000,
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", params,
+	)
+}
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"location-svc/internal/apperror"
+
+	"github.com/lib/pq"
+)
+
+// ReadRouter memilih pool koneksi untuk setiap query baca, mis. *db.ReplicaSet
+type ReadRouter interface {
+	Read() *sql.DB
+	Primary() *sql.DB
+	// MarkUnhealthy mengeluarkan pool db dari rotasi Read setelah query di pool tersebut gagal
+	MarkUnhealthy(db *sql.DB, err error)
+}
+
+// SetReadRouter mengarahkan query LocationRepository ke pool yang dipilih router, mis. read
+// replica. Tanpa SetReadRouter semua query memakai db dari NewLocationRepository.
+func (r *LocationRepository) SetReadRouter(router ReadRouter) {
+	r.router = router
+}
+
+// reader mengembalikan pool koneksi untuk satu operasi baca. Satu operasi (termasuk transaksinya)
+// selalu berjalan di satu pool agar hasilnya konsisten.
+func (r *LocationRepository) reader() *sql.DB {
+	if r.router == nil {
+		return r.db
+	}
+	return r.router.Read()
+}
+
+// read menjalankan op di pool dari reader. Jika op gagal di replica karena koneksi terputus atau
+// konflik dengan replikasi (lihat replicaFailure), replica dikeluarkan dari rotasi dan op diulang
+// sekali di primary tanpa menunggu pemeriksaan berkala ReplicaSet.Watch. op harus bisa diulang
+// dari awal; op yang sudah meneruskan hasil ke pemanggil menandai error-nya dengan streamed.
+func (r *LocationRepository) read(ctx context.Context, op func(q queryer) error) error {
+	db := r.reader()
+	err := op(r.conn(db))
+
+	var partial *streamedError
+	if errors.As(err, &partial) {
+		return partial.err
+	}
+	if r.router == nil || ctx.Err() != nil || !replicaFailure(err) {
+		return err
+	}
+
+	primary := r.router.Primary()
+	if db == primary {
+		return err
+	}
+	r.router.MarkUnhealthy(db, err)
+
+	err = op(r.conn(primary))
+	if errors.As(err, &partial) {
+		return partial.err
+	}
+	return err
+}
+
+// streamedError menandai error yang terjadi setelah sebagian hasil diteruskan ke pemanggil,
+// sehingga read tidak boleh mengulang op
+type streamedError struct {
+	err error
+}
+
+func (e *streamedError) Error() string { return e.err.Error() }
+func (e *streamedError) Unwrap() error { return e.err }
+
+// streamed membungkus err dengan streamedError jika sudah ada hasil yang diteruskan (sent > 0)
+func streamed(sent int, err error) error {
+	if err == nil || sent == 0 {
+		return err
+	}
+	return &streamedError{err: err}
+}
+
+// replicaFailure melaporkan apakah err berasal dari pool yang sebaiknya tidak dipakai lagi:
+// koneksi gagal (apperror.CodeUnavailable dari dbError) atau serialization_failure (40001),
+// yang di replica berarti query dibatalkan karena konflik dengan WAL yang sedang di-replay
+func replicaFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "40001" {
+		return true
+	}
+
+	appErr, ok := apperror.As(dbError(err))
+	return ok && appErr.Code == apperror.CodeUnavailable
+}
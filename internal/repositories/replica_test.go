@@ -0,0 +1,124 @@
+package repositories_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"location-svc/internal/repositories"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// versionDriver menjawab setiap query dengan satu baris dataset_version, kecuali DSN yang
+// terdaftar di failures yang selalu gagal dengan error tersebut
+type versionDriver struct {
+	failures map[string]error
+}
+
+var fakeVersions = &versionDriver{failures: map[string]error{
+	"replica-badconn":       driver.ErrBadConn,
+	"replica-serialization": &pq.Error{Code: "40001", Message: "canceling statement due to conflict with recovery"},
+	"replica-syntax":        &pq.Error{Code: "42601", Message: "syntax error"},
+}}
+
+func init() {
+	sql.Register("fake-versions", fakeVersions)
+}
+
+func (d *versionDriver) Open(dsn string) (driver.Conn, error) {
+	return &versionConn{err: d.failures[dsn]}, nil
+}
+
+type versionConn struct {
+	err error
+}
+
+func (c *versionConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &versionRows{}, nil
+}
+
+func (c *versionConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *versionConn) Close() error                        { return nil }
+func (c *versionConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+type versionRows struct {
+	done bool
+}
+
+func (r *versionRows) Columns() []string { return []string{"version", "updated_at"} }
+func (r *versionRows) Close() error      { return nil }
+
+func (r *versionRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0], dest[1] = int64(7), time.Unix(0, 0)
+	return nil
+}
+
+// stubRouter selalu mengarahkan query baca ke replica dan mencatat pool yang ditandai tidak sehat
+type stubRouter struct {
+	primary, replica *sql.DB
+	marked           []*sql.DB
+}
+
+func (s *stubRouter) Read() *sql.DB    { return s.replica }
+func (s *stubRouter) Primary() *sql.DB { return s.primary }
+
+func (s *stubRouter) MarkUnhealthy(db *sql.DB, err error) {
+	s.marked = append(s.marked, db)
+}
+
+func openVersions(t *testing.T, dsn string) *sql.DB {
+	t.Helper()
+	database, err := sql.Open("fake-versions", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func TestReadRetriesReplicaFailureOnPrimary(t *testing.T) {
+	primary := openVersions(t, "primary")
+
+	for _, dsn := range []string{"replica-badconn", "replica-serialization"} {
+		t.Run(dsn, func(t *testing.T) {
+			router := &stubRouter{primary: primary, replica: openVersions(t, dsn)}
+			repo := repositories.NewLocationRepository(primary)
+			repo.SetReadRouter(router)
+
+			v, err := repo.DatasetVersion(context.Background())
+			if err != nil {
+				t.Fatalf("DatasetVersion() error = %v, want retry on primary", err)
+			}
+			if v.Version != 7 {
+				t.Errorf("Version = %d, want 7", v.Version)
+			}
+			if len(router.marked) != 1 || router.marked[0] != router.replica {
+				t.Errorf("MarkUnhealthy called for %v, want the replica once", router.marked)
+			}
+		})
+	}
+}
+
+func TestReadDoesNotRetryQueryErrors(t *testing.T) {
+	router := &stubRouter{primary: openVersions(t, "primary"), replica: openVersions(t, "replica-syntax")}
+	repo := repositories.NewLocationRepository(router.primary)
+	repo.SetReadRouter(router)
+
+	if _, err := repo.DatasetVersion(context.Background()); err == nil {
+		t.Fatal("DatasetVersion() should return the replica error")
+	}
+	if len(router.marked) != 0 {
+		t.Error("a query error should not remove the replica from rotation")
+	}
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"location-svc/internal/models"
+	"strings"
+)
+
+// unifiedLevel mendeskripsikan satu level wilayah dalam pencarian gabungan
+type unifiedLevel struct {
+	level      string
+	query      pageQuery
+	breadcrumb string
+}
+
+// unifiedLevels berurutan dari level tertinggi; urutan ini menjadi tie-breaker hasil
+var unifiedLevels = []unifiedLevel{
+	{
+		level:      "propinsi",
+		query:      propinsiSearch,
+		breadcrumb: "nm_propinsi",
+	},
+	{
+		level:      "kabupaten",
+		query:      kabupatenSearch,
+		breadcrumb: "k.nm_kabupaten || ', ' || p.nm_propinsi",
+	},
+	{
+		level:      "kecamatan",
+		query:      kecamatanSearch,
+		breadcrumb: "kec.nm_kecamatan || ', ' || k.nm_kabupaten || ', ' || p.nm_propinsi",
+	},
+	{
+		level:      "kelurahan",
+		query:      kelurahanSearch,
+		breadcrumb: "kel.nm_kelurahan || ', ' || kec.nm_kecamatan || ', ' || k.nm_kabupaten || ', ' || p.nm_propinsi",
+	},
+}
+
+// Search mencari nama wilayah di semua level sekaligus untuk autocomplete.
+// Setiap level diambil paling banyak limit baris teratas memakai index trigram masing-masing,
+// lalu digabung dan diurutkan berdasarkan relevansi; pada relevansi yang sama level yang
+// lebih tinggi didahulukan.
+func (r *LocationRepository) Search(ctx context.Context, q string, limit int) ([]models.SearchResult, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Search)
+	defer cancel()
+
+	parts := make([]string, len(unifiedLevels))
+	for i, l := range unifiedLevels {
+		var filter searchFilter
+		filter.add(fuzzyCond(l.query.nameCol), q)
+
+		parts[i] = fmt.Sprintf(`(SELECT %d AS depth, '%s' AS level, %s AS code, %s AS name, %s AS breadcrumb,
+				%s AS rank, %s AS score
+			%s%s
+			ORDER BY rank, score DESC, name, code
+			LIMIT $2)`,
+			i, l.level, l.query.codeCol, l.query.nameCol, l.breadcrumb,
+			l.query.rankExpr(), l.query.scoreExpr(true), l.query.from, filter.where())
+	}
+
+	query := `SELECT level, code, name, breadcrumb, score
+		FROM (` + strings.Join(parts, " UNION ALL ") + `) s
+		ORDER BY rank, score DESC, depth, name, code
+		LIMIT $2`
+
+	var results []models.SearchResult
+	err := r.read(ctx, func(db queryer) error {
+		rows, err := db.QueryContext(ctx, query, q, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		results = []models.SearchResult{}
+		for rows.Next() {
+			var result models.SearchResult
+			if err := rows.Scan(&result.Level, &result.Code, &result.Name, &result.Breadcrumb, &result.Score); err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	return results, nil
+}
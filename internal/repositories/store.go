@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"location-svc/internal/models"
+)
+
+// LocationStore adalah kontrak akses data wilayah yang dipakai handler.
+// LocationRepository mengimplementasikannya dengan PostGIS, MemoryStore secara in-memory untuk testing.
+// Setiap method menerima context request; query dibatalkan saat ctx selesai.
+type LocationStore interface {
+	GetPropinsi(ctx context.Context, page models.PageParams) (*models.Page[models.Propinsi], error)
+	SearchPropinsiByName(ctx context.Context, name string, page models.PageParams) (*models.Page[models.Propinsi], error)
+	GetKabupaten(ctx context.Context, propinsiID string, page models.PageParams) (*models.Page[models.Kabupaten], error)
+	SearchKabupatenByName(ctx context.Context, name string, propinsiID *string, page models.PageParams) (*models.Page[models.Kabupaten], error)
+	GetKecamatan(ctx context.Context, propinsiID, kabupatenID string, page models.PageParams) (*models.Page[models.Kecamatan], error)
+	SearchKecamatanByName(ctx context.Context, name string, propinsiID, kabupatenID *string, page models.PageParams) (*models.Page[models.Kecamatan], error)
+	GetKelurahan(ctx context.Context, propinsiID, kabupatenID, kecamatanID string, page models.PageParams) (*models.Page[models.Kelurahan], error)
+	SearchKelurahanByName(ctx context.Context, name string, propinsiID, kabupatenID, kecamatanID *string, page models.PageParams) (*models.Page[models.Kelurahan], error)
+	Search(ctx context.Context, q string, limit int) ([]models.SearchResult, error)
+
+	GetPropinsiGeoJSON(ctx context.Context, id string, tolerance float64) (*models.GeoJSONFeature, error)
+	GetKabupatenGeoJSON(ctx context.Context, id string, tolerance float64) (*models.GeoJSONFeature, error)
+	GetKecamatanGeoJSON(ctx context.Context, id string, tolerance float64) (*models.GeoJSONFeature, error)
+	GetKelurahanGeoJSON(ctx context.Context, id string, tolerance float64) (*models.GeoJSONFeature, error)
+	GetKabupatenFeatureCollection(ctx context.Context, propinsiID string, tolerance float64, fn func(*models.GeoJSONFeature) error) error
+	GetKecamatanFeatureCollection(ctx context.Context, kabupatenID string, tolerance float64, fn func(*models.GeoJSONFeature) error) error
+	GetKelurahanFeatureCollection(ctx context.Context, kecamatanID string, tolerance float64, fn func(*models.GeoJSONFeature) error) error
+
+	ReverseGeocode(ctx context.Context, lat, lon float64) (*models.Kelurahan, error)
+	BatchReverseGeocode(ctx context.Context, lats, lons []float64) ([]*models.Kelurahan, error)
+
+	GetTile(ctx context.Context, level string, z, x, y int) ([]byte, error)
+
+	DatasetVersion(ctx context.Context) (*models.DatasetVersion, error)
+
+	ExportLevel(ctx context.Context, level, parentID string, tolerance float64, encoding GeometryEncoding, fn func(models.ExportRow) error) error
+}
+
+var (
+	_ LocationStore = (*LocationRepository)(nil)
+	_ LocationStore = (*MemoryStore)(nil)
+	_ LocationStore = (*CachedStore)(nil)
+	_ LocationStore = (*InstrumentedStore)(nil)
+)
@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"location-svc/internal/apperror"
+	"location-svc/internal/models"
+	"strings"
+)
+
+// TileLevelAuto memilih level administratif berdasarkan zoom (lihat TileLevelForZoom)
+const TileLevelAuto = "auto"
+
+// MaxTileZoom adalah zoom level tertinggi yang dilayani endpoint tile
+const MaxTileZoom = 22
+
+// tileSource mendefinisikan query sumber untuk satu level vector tile
+type tileSource struct {
+	query   string
+	fields  []string
+	minZoom int
+	maxZoom int
+}
+
+// tileSources berisi sumber data per level beserta rentang zoom saat level dipilih otomatis
+var tileSources = map[string]tileSource{
+	"propinsi": {
+		query:   `SELECT p.geom, p.kd_propinsi, p.nm_propinsi FROM propinsi p`,
+		fields:  []string{"kd_propinsi", "nm_propinsi"},
+		minZoom: 0,
+		maxZoom: 5,
+	},
+	"kabupaten": {
+		query: `SELECT k.geom, p.kd_propinsi, p.nm_propinsi, k.kd_kabupaten, k.nm_kabupaten
+			FROM kabupaten k
+			JOIN propinsi p ON k.kd_propinsi = p.kd_propinsi`,
+		fields:  []string{"kd_propinsi", "nm_propinsi", "kd_kabupaten", "nm_kabupaten"},
+		minZoom: 6,
+		maxZoom: 8,
+	},
+	"kecamatan": {
+		query: `SELECT kec.geom, p.kd_propinsi, p.nm_propinsi, k.kd_kabupaten, k.nm_kabupaten,
+			       kec.kd_kecamatan, kec.nm_kecamatan
+			FROM kecamatan kec
+			JOIN kabupaten k ON kec.kd_kabupaten = k.kd_kabupaten
+			JOIN propinsi p ON k.kd_propinsi = p.kd_propinsi`,
+		fields:  []string{"kd_propinsi", "nm_propinsi", "kd_kabupaten", "nm_kabupaten", "kd_kecamatan", "nm_kecamatan"},
+		minZoom: 9,
+		maxZoom: 11,
+	},
+	"kelurahan": {
+		query: `SELECT kel.geom, p.kd_propinsi, p.nm_propinsi, k.kd_kabupaten, k.nm_kabupaten,
+			       kec.kd_kecamatan, kec.nm_kecamatan, kel.kd_kelurahan, kel.nm_kelurahan
+			FROM kelurahan kel
+			JOIN kecamatan kec ON kel.kd_kecamatan = kec.kd_kecamatan
+			JOIN kabupaten k ON kec.kd_kabupaten = k.kd_kabupaten
+			JOIN propinsi p ON k.kd_propinsi = p.kd_propinsi`,
+		fields: []string{"kd_propinsi", "nm_propinsi", "kd_kabupaten", "nm_kabupaten",
+			"kd_kecamatan", "nm_kecamatan", "kd_kelurahan", "nm_kelurahan"},
+		minZoom: 12,
+		maxZoom: MaxTileZoom,
+	},
+}
+
+// tileLevelOrder adalah urutan level dari yang paling kasar ke paling detail
+var tileLevelOrder = []string{"propinsi", "kabupaten", "kecamatan", "kelurahan"}
+
+// IsTileLevel mengecek apakah level valid untuk endpoint tile (termasuk "auto")
+func IsTileLevel(level string) bool {
+	_, ok := tileSources[level]
+	return ok || level == TileLevelAuto
+}
+
+// TileLevelForZoom mengembalikan level administratif yang ditampilkan pada zoom tertentu
+func TileLevelForZoom(z int) string {
+	for _, level := range tileLevelOrder {
+		if z <= tileSources[level].maxZoom {
+			return level
+		}
+	}
+	return tileLevelOrder[len(tileLevelOrder)-1]
+}
+
+// TileVectorLayers mengembalikan deskripsi layer TileJSON untuk level tertentu.
+// Level "auto" menghasilkan semua layer dengan rentang zoom masing-masing.
+func TileVectorLayers(level string) []models.VectorLayer {
+	levels := []string{level}
+	if level == TileLevelAuto {
+		levels = tileLevelOrder
+	}
+
+	var layers []models.VectorLayer
+	for _, l := range levels {
+		src := tileSources[l]
+		fields := make(map[string]string, len(src.fields))
+		for _, f := range src.fields {
+			fields[f] = "String"
+		}
+
+		layer := models.VectorLayer{ID: l, Fields: fields, MinZoom: 0, MaxZoom: MaxTileZoom}
+		if level == TileLevelAuto {
+			layer.MinZoom, layer.MaxZoom = src.minZoom, src.maxZoom
+		}
+		layers = append(layers, layer)
+	}
+
+	return layers
+}
+
+// GetTile mendapatkan Mapbox Vector Tile (MVT) untuk level dan koordinat tile z/x/y.
+// Nama layer di dalam tile sama dengan nama level yang dipakai.
+func (r *LocationRepository) GetTile(ctx context.Context, level string, z, x, y int) ([]byte, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Tiles)
+	defer cancel()
+
+	if level == TileLevelAuto {
+		level = TileLevelForZoom(z)
+	}
+
+	src, ok := tileSources[level]
+	if !ok {
+		return nil, apperror.InvalidArgument(fmt.Sprintf("Unknown tile level: %s", level))
+	}
+
+	// Filter bbox dilakukan pada SRID 4326 agar GIST index pada geom tetap terpakai
+	query := `
+		WITH bounds AS (SELECT ST_TileEnvelope($1, $2, $3) AS geom),
+		src AS (` + src.query + `),
+		mvtgeom AS (
+			SELECT ST_AsMVTGeom(ST_Transform(src.geom, 3857), bounds.geom) AS geom, ` + strings.Join(src.fields, ", ") + `
+			FROM src, bounds
+			WHERE src.geom && ST_Transform(bounds.geom, 4326)
+		)
+		SELECT ST_AsMVT(mvtgeom.*, '` + level + `', 4096, 'geom') FROM mvtgeom`
+
+	var tile []byte
+	err := r.read(ctx, func(q queryer) error {
+		return q.QueryRowContext(ctx, query, z, x, y).Scan(&tile)
+	})
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	return tile, nil
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// Timeouts adalah batas waktu query LocationRepository per kelompok operasi, dihitung dari
+// awal pemanggilan method. Query yang melewati batas dibatalkan di server dan menghasilkan
+// apperror.CodeTimeout (HTTP 504). Nilai 0 berarti hanya dibatasi context pemanggil.
+type Timeouts struct {
+	// Search berlaku untuk daftar wilayah, pencarian dan versi dataset
+	Search  time.Duration
+	GeoJSON time.Duration
+	Tiles   time.Duration
+	Reverse time.Duration
+	// Export berlaku untuk seluruh stream export, bukan per baris
+	Export time.Duration
+}
+
+// SetTimeouts mengatur batas waktu query; tanpa SetTimeouts query hanya dibatasi context pemanggil
+func (r *LocationRepository) SetTimeouts(t Timeouts) {
+	r.timeouts = t
+}
+
+// withTimeout menurunkan ctx dengan batas waktu d, atau mengembalikan ctx apa adanya jika d <= 0
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
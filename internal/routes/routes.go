@@ -1,40 +1,77 @@
 package routes
 
 import (
-	"database/sql"
+	"location-svc/internal/config"
 	"location-svc/internal/handlers"
+	"location-svc/internal/health"
 	"location-svc/internal/repositories"
+	"net/http"
 
 	"github.com/labstack/echo/v4"
 )
 
-// Setup menginisialisasi semua routes untuk aplikasi
-func Setup(e *echo.Echo, db *sql.DB) {
-	// Initialize repository
-	locationRepo := repositories.NewLocationRepository(db)
+// Setup menginisialisasi semua routes untuk aplikasi. state menjalankan pemeriksaan /readyz;
+// setelah state.StartShutdown /readyz dan /health menjawab 503.
+func Setup(e *echo.Echo, store repositories.LocationStore, cfg *config.Config, state *health.State) {
+	cacheControl := cfg.Cache.Control
 
 	// Initialize handler
-	locationHandler := handlers.NewLocationHandler(locationRepo)
+	locationHandler := handlers.NewLocationHandler(store)
+
+	// ETag/Last-Modified dari versi dataset untuk semua endpoint baca
+	conditional := handlers.NewConditionalGet(store)
 
 	// Search endpoints (Tag: search)
-	searchGroup := e.Group("/search")
+	searchGroup := e.Group("/search", conditional.Middleware(cacheControl.Search))
+	searchGroup.GET("", locationHandler.Search)
 	searchGroup.GET("/propinsi", locationHandler.GetPropinsi)
 	searchGroup.GET("/kabupaten", locationHandler.GetKabupaten)
 	searchGroup.GET("/kecamatan", locationHandler.GetKecamatan)
 	searchGroup.GET("/kelurahan", locationHandler.GetKelurahan)
 
 	// GeoJSON endpoints (Tag: geojson)
-	geojsonGroup := e.Group("/geojson")
+	geojsonGroup := e.Group("/geojson", conditional.Middleware(cacheControl.GeoJSON))
 	geojsonGroup.GET("/propinsi/:id", locationHandler.GetPropinsiGeoJSON)
 	geojsonGroup.GET("/kabupaten/:id", locationHandler.GetKabupatenGeoJSON)
 	geojsonGroup.GET("/kecamatan/:id", locationHandler.GetKecamatanGeoJSON)
 	geojsonGroup.GET("/kelurahan/:id", locationHandler.GetKelurahanGeoJSON)
+	geojsonGroup.GET("/propinsi/:id/kabupaten", locationHandler.GetPropinsiKabupatenGeoJSON)
+	geojsonGroup.GET("/kabupaten/:id/kecamatan", locationHandler.GetKabupatenKecamatanGeoJSON)
+	geojsonGroup.GET("/kecamatan/:id/kelurahan", locationHandler.GetKecamatanKelurahanGeoJSON)
+
+	// Vector tile endpoints (Tag: tiles)
+	tilesGroup := e.Group("/tiles", conditional.Middleware(cacheControl.Tiles))
+	tilesGroup.GET("/:level/tile.json", locationHandler.GetTileJSON)
+	tilesGroup.GET("/:level/:z/:x/:y", locationHandler.GetTile)
+
+	// Export endpoints (Tag: export)
+	exportGroup := e.Group("/export", conditional.Middleware(cacheControl.Export))
+	exportGroup.GET("/:level", locationHandler.ExportLevel)
+
+	// Reverse geocoding endpoints (Tag: reverse)
+	reverseGroup := e.Group("/reverse", conditional.Middleware(cacheControl.Reverse))
+	reverseGroup.GET("", locationHandler.ReverseGeocode)
+	reverseGroup.POST("/batch", locationHandler.BatchReverseGeocode)
+
+	// Liveness dan readiness probe (Tag: health)
+	healthHandler := handlers.NewHealthHandler(state)
+	e.GET("/livez", healthHandler.Livez)
+	e.GET("/readyz", healthHandler.Readyz)
 
-	// Health check endpoint
+	// Health check lama tanpa pemeriksaan database, termasuk counter cache GeoJSON jika store memakai cache
 	e.GET("/health", func(c echo.Context) error {
-		return c.JSON(200, map[string]string{
+		status := http.StatusOK
+		report := map[string]interface{}{
 			"status":  "OK",
 			"service": "Location Service",
-		})
+		}
+		if state.ShuttingDown() {
+			status = http.StatusServiceUnavailable
+			report["status"] = "SHUTTING_DOWN"
+		}
+		if cached, ok := store.(*repositories.CachedStore); ok {
+			report["geojson_cache"] = cached.CacheStats()
+		}
+		return c.JSON(status, report)
 	})
 }
@@ -48,7 +48,7 @@ swag init -g cmd/main.go
 
 # Build the application to check for errors
 echo "🔨 Building application..."
-go build -o bin/location-svc cmd/main.go
+go build -o bin/location-svc ./cmd
 
 echo ""
 echo "✅ Setup complete!"